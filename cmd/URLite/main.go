@@ -1,19 +1,35 @@
 package main
 
 import (
+	"URLite/internal/analytics"
 	"URLite/internal/config"
-	"URLite/internal/http-server/handlers/delete"
-	"URLite/internal/http-server/handlers/redirect"
+	httpserver "URLite/internal/http-server"
+	"URLite/internal/http-server/handlers/health"
 	"URLite/internal/http-server/handlers/url/save"
-	mwLogger "URLite/internal/http-server/middleware/logger"
+	userCreate "URLite/internal/http-server/handlers/user/create"
+	"URLite/internal/http-server/middleware/auth"
+	"URLite/internal/janitor"
+	"URLite/internal/lib/alias"
 	"URLite/internal/lib/logger/handlers/slogpretty"
 	"URLite/internal/lib/logger/sl"
+	"URLite/internal/lib/password"
+	"URLite/internal/metrics"
+	urlstorage "URLite/internal/storage"
+	"URLite/internal/storage/cache"
+	"URLite/internal/storage/instrumented"
+	"URLite/internal/storage/memory"
+	"URLite/internal/storage/postgres"
 	"URLite/internal/storage/sqlite"
-	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
+	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 )
 
 const (
@@ -22,6 +38,25 @@ const (
 	envProd  = "prod"
 )
 
+const (
+	driverSQLite   = "sqlite"
+	driverPostgres = "postgres"
+	driverMemory   = "memory"
+
+	// memoryStoragePath в storage_path включает хранилище в памяти вместо файла SQLite.
+	memoryStoragePath = ":memory:"
+)
+
+// urlStorage — хранилище, которое используют обработчики и фоновые процессы сервиса.
+type urlStorage interface {
+	httpserver.Storage
+	instrumented.Backend
+	analytics.ClickSaver
+	janitor.ExpiredDeleter
+	Ping(ctx context.Context) error
+	Close() error
+}
+
 func main() {
 	// TODO: init config: cleanenv
 	cfg := config.MustLoad()
@@ -32,43 +67,95 @@ func main() {
 
 	log := setupLogger(cfg.Env)
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(cfg, os.Args[2:]))
+	}
+
 	log.Info("starting URLite", slog.String("env", cfg.Env))
 	log.Debug("debag messages are enabled")
 
 	// TODO: init storage: sqlite
 
-	storage, err := sqlite.New(cfg.StoragePath)
+	storage, err := setupStorage(cfg, log)
 	if err != nil {
 		log.Error("failed to init storage", sl.Err(err))
 		os.Exit(1)
 	}
 
-	_ = storage
+	driver := storageDriver(cfg)
 
-	// TODO: init router: chi, "chi render"
+	log.Info("storage initialized", slog.String("driver", driver))
 
-	router := chi.NewRouter()
+	healthState := &health.State{}
+
+	readinessChecks, err := setupReadinessChecks(storage)
+	if err != nil {
+		log.Error("failed to init readiness checks", sl.Err(err))
+		os.Exit(1)
+	}
 
-	// middleware
+	appMetrics := metrics.New()
 
-	router.Use(middleware.RequestID)
-	router.Use(middleware.Logger)
-	router.Use(mwLogger.New(log))
-	router.Use(middleware.Recoverer)
-	router.Use(middleware.URLFormat)
+	storage = instrumented.New(storage, driver, appMetrics)
 
-	router.Route("/url", func(r chi.Router) {
-		r.Use(middleware.BasicAuth("url-shortener", map[string]string{
-			cfg.HTTPServer.User: cfg.HTTPServer.Password,
-		}))
+	// Все изменения ссылок проходят через кэш, чтобы он не отдавал устаревшие адреса
+	urlCache := cache.New(storage, cache.Options{
+		Size:        cfg.Cache.Size,
+		TTL:         cfg.Cache.TTL,
+		NegativeTTL: cfg.Cache.NegativeTTL,
+	})
 
-		r.Post("/", save.New(log, storage))
-		r.Delete("/url/{alias}", delete.New(log, storage))
+	clickRecorder := analytics.NewRecorder(log, storage, analytics.Options{
+		BufferSize:    cfg.Analytics.BufferSize,
+		BatchSize:     cfg.Analytics.BatchSize,
+		FlushInterval: cfg.Analytics.FlushInterval,
 	})
 
-	router.Post("/url", save.New(log, storage))
-	router.Get("/{alias}", redirect.New(log, storage))
-	router.Delete("/url/{alias}", delete.New(log, storage))
+	// Фоновые процессы останавливаются только после HTTP-сервера,
+	// чтобы сохранить переходы из запросов, обрабатываемых в момент остановки
+	bgCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+
+	var background sync.WaitGroup
+
+	background.Add(2)
+	go func() {
+		defer background.Done()
+		clickRecorder.Run(bgCtx)
+	}()
+	go func() {
+		defer background.Done()
+		janitor.New(log, storage, cfg.Janitor.Interval).Run(bgCtx)
+	}()
+
+	setupCollectors(appMetrics, urlCache, clickRecorder)
+
+	if err := ensureAdmin(log, storage, cfg.HTTPServer.User, cfg.HTTPServer.Password); err != nil {
+		log.Error("failed to create admin user", sl.Err(err))
+		os.Exit(1)
+	}
+
+	aliasGenerator, err := setupAliasGenerator(cfg.Alias)
+	if err != nil {
+		log.Error("failed to init alias generator", sl.Err(err))
+		os.Exit(1)
+	}
+
+	// TODO: init router: chi, "chi render"
+
+	router, err := httpserver.NewRouter(log, cfg, httpserver.Deps{
+		Storage:         storage,
+		URLCache:        urlCache,
+		AliasGenerator:  aliasGenerator,
+		ClickRecorder:   clickRecorder,
+		Metrics:         appMetrics,
+		HealthState:     healthState,
+		ReadinessChecks: readinessChecks,
+	})
+	if err != nil {
+		log.Error("failed to init router", sl.Err(err))
+		os.Exit(1)
+	}
 
 	log.Info("starting server", slog.String("address", cfg.Address))
 
@@ -80,13 +167,42 @@ func main() {
 		IdleTimeout:  cfg.HTTPServer.IdleTimeout,
 	}
 
-	if err := srv.ListenAndServe(); err != nil {
-		log.Error("failed to start server")
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		log.Info("stopping server")
+	case err := <-serverErr:
+		log.Error("failed to start server", sl.Err(err))
 	}
 
-	log.Error("server stopped")
+	// Балансировщик перестаёт направлять трафик, пока сервер дорабатывает запросы
+	healthState.Shutdown()
 
-	// TODO: run server
+	// Сервер перестаёт принимать соединения и дожидается обрабатываемых запросов
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.HTTPServer.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Error("failed to stop server gracefully", sl.Err(err))
+	}
+
+	stopBackground()
+	background.Wait()
+
+	if err := storage.Close(); err != nil {
+		log.Error("failed to close storage", sl.Err(err))
+	}
+
+	log.Info("server stopped")
 }
 
 func setupLogger(env string) *slog.Logger {
@@ -107,6 +223,166 @@ func setupLogger(env string) *slog.Logger {
 	return log
 }
 
+// storageDriver возвращает драйвер хранилища с учётом storage_path: ":memory:" включает хранилище в памяти.
+func storageDriver(cfg *config.Config) string {
+	if cfg.Storage.Driver == driverSQLite && cfg.StoragePath == memoryStoragePath {
+		return driverMemory
+	}
+
+	return cfg.Storage.Driver
+}
+
+func setupStorage(cfg *config.Config, log *slog.Logger) (urlStorage, error) {
+	switch storageDriver(cfg) {
+	case driverSQLite:
+		if cfg.StoragePath == "" {
+			return nil, errors.New("storage_path is required for the sqlite driver")
+		}
+
+		s, err := sqlite.New(cfg.StoragePath)
+		if err != nil {
+			return nil, err
+		}
+
+		if cfg.Storage.AutoMigrate {
+			m, err := s.Migrator()
+			if err != nil {
+				_ = s.Close()
+				return nil, err
+			}
+
+			applied, err := m.Up()
+			if err != nil {
+				_ = s.Close()
+				return nil, err
+			}
+
+			for _, mig := range applied {
+				log.Info("migration applied", slog.Int("version", mig.Version), slog.String("name", mig.Name))
+			}
+		}
+
+		return s, nil
+	case driverPostgres:
+		if cfg.Storage.DSN == "" {
+			return nil, errors.New("storage.dsn is required for the postgres driver")
+		}
+		return postgres.New(cfg.Storage.DSN)
+	case driverMemory:
+		return memory.New(), nil
+	default:
+		return nil, fmt.Errorf("unknown storage driver: %q", cfg.Storage.Driver)
+	}
+}
+
+// setupAliasGenerator возвращает генератор псевдонимов выбранной в конфигурации стратегии.
+func setupAliasGenerator(cfg config.Alias) (save.AliasGenerator, error) {
+	switch cfg.Strategy {
+	case alias.StrategyRandom:
+		return alias.NewRandom(cfg.Length, cfg.MaxLength), nil
+	case alias.StrategyBase62:
+		return alias.Base62{}, nil
+	case alias.StrategySqids:
+		g, err := alias.NewSqids(cfg.Alphabet, cfg.Length, cfg.Blocklist)
+		if err != nil {
+			return nil, err
+		}
+		return g, nil
+	case alias.StrategyWords:
+		return alias.NewWords(), nil
+	default:
+		return nil, fmt.Errorf("unknown alias strategy: %q", cfg.Strategy)
+	}
+}
+
+// adminStorage — хранилище пользователей, в котором создаётся администратор.
+type adminStorage interface {
+	auth.UserGetter
+	userCreate.UserSaver
+}
+
+// ensureAdmin создаёт администратора с логином и паролем из конфигурации, если его ещё нет.
+// Пароль существующего пользователя не меняется.
+func ensureAdmin(log *slog.Logger, users adminStorage, username, pass string) error {
+	_, err := users.GetUser(username)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, urlstorage.ErrUserNotFound) {
+		return err
+	}
+
+	hash, err := password.Hash(pass)
+	if err != nil {
+		return err
+	}
+
+	_, err = users.SaveUser(urlstorage.User{
+		Username:     username,
+		PasswordHash: hash,
+		Role:         urlstorage.RoleAdmin,
+		CreatedAt:    time.Now().UTC(),
+	})
+	// Администратора мог одновременно создать другой экземпляр сервиса
+	if errors.Is(err, urlstorage.ErrUserExists) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Info("admin user created", slog.String("user", username))
+
+	return nil
+}
+
+// setupReadinessChecks возвращает проверки зависимостей, без которых сервис не готов принимать трафик.
+func setupReadinessChecks(storage urlStorage) (map[string]health.Checker, error) {
+	checks := map[string]health.Checker{
+		"storage": health.CheckerFunc(storage.Ping),
+	}
+
+	if s, ok := storage.(*sqlite.Storage); ok {
+		m, err := s.Migrator()
+		if err != nil {
+			return nil, err
+		}
+
+		checks["migrations"] = health.CheckerFunc(func(context.Context) error {
+			pending, err := m.Pending()
+			if err != nil {
+				return err
+			}
+			if pending > 0 {
+				return fmt.Errorf("%d pending migrations", pending)
+			}
+
+			return nil
+		})
+	}
+
+	return checks, nil
+}
+
+// setupCollectors публикует в метриках счётчики кэша и записи переходов.
+func setupCollectors(m *metrics.Metrics, urlCache *cache.Cache, clickRecorder *analytics.Recorder) {
+	m.CounterFunc("cache_hits_total", "Number of redirects served from the url cache.", func() float64 {
+		return float64(urlCache.Stats().Hits)
+	})
+	m.CounterFunc("cache_misses_total", "Number of redirects that had to load the url from storage.", func() float64 {
+		return float64(urlCache.Stats().Misses)
+	})
+	m.CounterFunc("cache_evictions_total", "Number of urls evicted from the full url cache.", func() float64 {
+		return float64(urlCache.Stats().Evictions)
+	})
+	m.GaugeFunc("cache_entries", "Number of urls currently held in the url cache.", func() float64 {
+		return float64(urlCache.Stats().Size)
+	})
+	m.CounterFunc("clicks_dropped_total", "Number of clicks dropped because the analytics queue was full.", func() float64 {
+		return float64(clickRecorder.Dropped())
+	})
+}
+
 func setupPrettySlog() *slog.Logger {
 	opts := slogpretty.PrettyHandlerOptions{
 		SlogOpts: &slog.HandlerOptions{
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"URLite/internal/config"
+	"URLite/internal/storage/migrator"
+	"URLite/internal/storage/sqlite"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+const migrateUsage = "usage: URLite migrate up|down|status"
+
+// runMigrate выполняет команду "migrate" и возвращает код завершения процесса.
+func runMigrate(cfg *config.Config, args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	if cfg.Storage.Driver != driverSQLite || cfg.StoragePath == memoryStoragePath {
+		fmt.Fprintln(os.Stderr, "migrations are only supported for the sqlite storage")
+		return 1
+	}
+
+	s, err := sqlite.New(cfg.StoragePath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer func() { _ = s.Close() }()
+
+	m, err := s.Migrator()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := m.Up()
+		for _, mig := range applied {
+			fmt.Printf("applied %04d_%s\n", mig.Version, mig.Name)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if len(applied) == 0 {
+			fmt.Println("no pending migrations")
+		}
+	case "down":
+		mig, err := m.Down()
+		if errors.Is(err, migrator.ErrNoApplied) {
+			fmt.Println("no applied migrations")
+			return 0
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Printf("rolled back %04d_%s\n", mig.Version, mig.Name)
+	case "status":
+		statuses, err := m.Status()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		for _, st := range statuses {
+			state := "pending"
+			if st.AppliedAt != nil {
+				state = "applied " + st.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%04d_%s\t%s\n", st.Version, st.Name, state)
+		}
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	return 0
+}
@@ -0,0 +1,115 @@
+package analytics
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"URLite/internal/lib/logger/sl"
+	"URLite/internal/storage"
+)
+
+// ClickSaver — интерфейс хранилища событий переходов.
+type ClickSaver interface {
+	SaveClicks(clicks []storage.Click) error
+}
+
+// Options — параметры буферизованной записи событий.
+type Options struct {
+	// BufferSize — ёмкость очереди событий. При переполнении новые события отбрасываются.
+	BufferSize int
+	// BatchSize — максимальное число событий, сохраняемых за одну транзакцию.
+	BatchSize int
+	// FlushInterval — как часто сбрасывать неполную пачку в хранилище.
+	FlushInterval time.Duration
+}
+
+// Recorder асинхронно сохраняет события переходов, не замедляя обработку редиректов.
+type Recorder struct {
+	log     *slog.Logger
+	saver   ClickSaver
+	opts    Options
+	events  chan storage.Click
+	dropped atomic.Int64
+}
+
+func NewRecorder(log *slog.Logger, saver ClickSaver, opts Options) *Recorder {
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = 1024
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = time.Second
+	}
+
+	return &Recorder{
+		log:    log.With(slog.String("component", "analytics/recorder")),
+		saver:  saver,
+		opts:   opts,
+		events: make(chan storage.Click, opts.BufferSize),
+	}
+}
+
+// RecordClick ставит событие в очередь. Метод никогда не блокируется:
+// если очередь заполнена, событие отбрасывается.
+func (r *Recorder) RecordClick(click storage.Click) {
+	select {
+	case r.events <- click:
+	default:
+		r.dropped.Add(1)
+	}
+}
+
+// Dropped возвращает число событий, отброшенных из-за переполнения очереди.
+func (r *Recorder) Dropped() int64 {
+	return r.dropped.Load()
+}
+
+// Run сохраняет события пачками, пока не будет отменён ctx.
+// Перед возвратом оставшиеся в очереди события сбрасываются в хранилище.
+func (r *Recorder) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]storage.Click, 0, r.opts.BatchSize)
+
+	for {
+		select {
+		case click := <-r.events:
+			batch = append(batch, click)
+			if len(batch) >= r.opts.BatchSize {
+				batch = r.flush(batch)
+			}
+		case <-ticker.C:
+			batch = r.flush(batch)
+		case <-ctx.Done():
+			for {
+				select {
+				case click := <-r.events:
+					batch = append(batch, click)
+					if len(batch) >= r.opts.BatchSize {
+						batch = r.flush(batch)
+					}
+				default:
+					r.flush(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+func (r *Recorder) flush(batch []storage.Click) []storage.Click {
+	if len(batch) == 0 {
+		return batch
+	}
+
+	if err := r.saver.SaveClicks(batch); err != nil {
+		r.log.Error("failed to save clicks", slog.Int("count", len(batch)), sl.Err(err))
+	}
+
+	return batch[:0]
+}
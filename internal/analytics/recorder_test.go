@@ -0,0 +1,97 @@
+package analytics_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"URLite/internal/analytics"
+	"URLite/internal/lib/logger/handlers/slogdiscard"
+	"URLite/internal/storage"
+	"github.com/stretchr/testify/require"
+)
+
+// clickSaverStub запоминает сохранённые пачки событий.
+type clickSaverStub struct {
+	mu      sync.Mutex
+	batches [][]storage.Click
+	err     error
+}
+
+func (s *clickSaverStub) SaveClicks(clicks []storage.Click) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.batches = append(s.batches, append([]storage.Click(nil), clicks...))
+
+	return s.err
+}
+
+func (s *clickSaverStub) saved() []storage.Click {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var all []storage.Click
+	for _, b := range s.batches {
+		all = append(all, b...)
+	}
+
+	return all
+}
+
+func TestRecorder_FlushesOnShutdown(t *testing.T) {
+	saver := &clickSaverStub{}
+	rec := analytics.NewRecorder(slogdiscard.NewDiscardLogger(), saver, analytics.Options{
+		BufferSize:    10,
+		BatchSize:     3,
+		FlushInterval: time.Hour,
+	})
+
+	for i := 0; i < 7; i++ {
+		rec.RecordClick(storage.Click{Alias: "alias"})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rec.Run(ctx)
+
+	require.Len(t, saver.saved(), 7)
+	for _, b := range saver.batches {
+		require.LessOrEqual(t, len(b), 3)
+	}
+}
+
+func TestRecorder_FlushesOnInterval(t *testing.T) {
+	saver := &clickSaverStub{}
+	rec := analytics.NewRecorder(slogdiscard.NewDiscardLogger(), saver, analytics.Options{
+		BufferSize:    10,
+		BatchSize:     100,
+		FlushInterval: 10 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go rec.Run(ctx)
+
+	rec.RecordClick(storage.Click{Alias: "alias"})
+
+	require.Eventually(t, func() bool {
+		return len(saver.saved()) == 1
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestRecorder_DropsWhenFull(t *testing.T) {
+	saver := &clickSaverStub{err: errors.New("unexpected error")}
+	rec := analytics.NewRecorder(slogdiscard.NewDiscardLogger(), saver, analytics.Options{
+		BufferSize: 2,
+	})
+
+	for i := 0; i < 5; i++ {
+		rec.RecordClick(storage.Click{Alias: "alias"})
+	}
+
+	require.EqualValues(t, 3, rec.Dropped())
+}
@@ -8,17 +8,121 @@ import (
 )
 
 type Config struct {
-	Env         string `yaml:"env" env-default:"local"`
-	StoragePath string `yaml:"storage_path" env-required:"true"`
-	HTTPServer  `yaml:"http_server"`
+	Env              string  `yaml:"env" env-default:"local"`
+	StoragePath      string  `yaml:"storage_path"`
+	Storage          Storage `yaml:"storage"`
+	HTTPServer       `yaml:"http_server"`
+	Analytics        Analytics        `yaml:"analytics"`
+	Janitor          Janitor          `yaml:"janitor"`
+	Cache            Cache            `yaml:"cache"`
+	RateLimit        RateLimit        `yaml:"rate_limit"`
+	Alias            Alias            `yaml:"alias"`
+	URLNormalization URLNormalization `yaml:"url_normalization"`
 }
 
 type HTTPServer struct {
 	Address     string        `yaml:"address" env-default:"localhost:8080"`
 	Timeout     time.Duration `yaml:"timeout" env-default:"4s"`
 	IdleTimeout time.Duration `yaml:"idle_timeout" env-default:"60s"`
-	User        string        `yaml:"user" env-required:"true"`
-	Password    string        `yaml:"password" env-required:"true" env:"HTTP_SERVER_PASSWORD"`
+	// ShutdownTimeout — сколько ждать завершения обрабатываемых запросов при остановке.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env-default:"10s"`
+	// User и Password — учётная запись администратора, которая создаётся при первом запуске.
+	User     string `yaml:"user" env-required:"true"`
+	Password string `yaml:"password" env-required:"true" env:"HTTP_SERVER_PASSWORD"`
+}
+
+// Storage — выбор хранилища ссылок.
+// Для sqlite используется StoragePath, для postgres — DSN.
+type Storage struct {
+	Driver string `yaml:"driver" env:"STORAGE_DRIVER" env-default:"sqlite"`
+	DSN    string `yaml:"dsn" env:"STORAGE_DSN"`
+	// AutoMigrate включает применение миграций схемы SQLite при запуске сервиса.
+	AutoMigrate bool `yaml:"auto_migrate" env-default:"true"`
+}
+
+// Analytics — настройки асинхронной записи переходов по ссылкам.
+type Analytics struct {
+	BufferSize    int           `yaml:"buffer_size" env-default:"1024"`
+	BatchSize     int           `yaml:"batch_size" env-default:"100"`
+	FlushInterval time.Duration `yaml:"flush_interval" env-default:"1s"`
+}
+
+// Janitor — настройки фонового удаления ссылок с истёкшим сроком действия.
+type Janitor struct {
+	// Interval — период удаления. Отрицательное значение отключает удаление,
+	// нулевое заменяется значением по умолчанию.
+	Interval time.Duration `yaml:"interval" env-default:"1m"`
+}
+
+// Cache — настройки кэша ссылок перед хранилищем.
+// Кэш локален для процесса, поэтому при нескольких репликах изменения
+// ссылки на других репликах становятся видны не позже чем через TTL.
+type Cache struct {
+	// Size — максимальное число ссылок в кэше. Отрицательное значение отключает кэш,
+	// нулевое заменяется значением по умолчанию.
+	Size int           `yaml:"size" env-default:"10000"`
+	TTL  time.Duration `yaml:"ttl" env-default:"5m"`
+	// NegativeTTL — сколько помнить несуществующий псевдоним. Отрицательное значение
+	// отключает запоминание, нулевое заменяется значением по умолчанию.
+	NegativeTTL time.Duration `yaml:"negative_ttl" env-default:"30s"`
+}
+
+// RateLimit — ограничение частоты запросов одного клиента корзиной маркеров:
+// в среднем Rate запросов в секунду и не больше Burst запросов подряд. Отрицательный Rate
+// отключает ограничение, нулевой заменяется значением по умолчанию.
+// Клиенты с ключом доступа различаются по ключу, остальные — по IP-адресу.
+type RateLimit struct {
+	// TrustedProxies — адреса и подсети прокси, которым можно доверять заголовок X-Forwarded-For.
+	TrustedProxies []string `yaml:"trusted_proxies"`
+	// Write* ограничивают создание, изменение и удаление ссылок.
+	WriteRate  float64 `yaml:"write_rate" env-default:"1"`
+	WriteBurst int     `yaml:"write_burst" env-default:"20"`
+	// Redirect* ограничивают переходы по коротким ссылкам.
+	RedirectRate  float64 `yaml:"redirect_rate" env-default:"50"`
+	RedirectBurst int     `yaml:"redirect_burst" env-default:"100"`
+	// Auth* ограничивают неудачные попытки входа с одного IP-адреса.
+	AuthRate  float64 `yaml:"auth_rate" env-default:"0.2"`
+	AuthBurst int     `yaml:"auth_burst" env-default:"10"`
+}
+
+// Alias — генерация псевдонимов для ссылок, сохраняемых без псевдонима.
+type Alias struct {
+	// Strategy — random, base62, sqids или words.
+	Strategy string `yaml:"strategy" env-default:"random"`
+	// Length — начальная длина случайных псевдонимов и минимальная длина псевдонимов sqids.
+	Length int `yaml:"length" env-default:"6"`
+	// MaxLength ограничивает рост длины случайных псевдонимов при частых коллизиях.
+	MaxLength int `yaml:"max_length" env-default:"16"`
+	// Alphabet — алфавит sqids, его порядок определяет псевдонимы. Пустой — алфавит по умолчанию.
+	Alphabet string `yaml:"alphabet"`
+	// Blocklist — слова, которых не должно быть в псевдонимах: псевдонимы клиентов с ними отклоняются,
+	// а сгенерированные заменяются другими.
+	Blocklist []string `yaml:"blocklist"`
+	// Custom — правила для псевдонимов, которые задают клиенты.
+	Custom CustomAlias `yaml:"custom"`
+}
+
+// CustomAlias — правила для псевдонимов, которые задают клиенты. Допустимы латинские буквы, цифры, '-' и '_'.
+type CustomAlias struct {
+	MinLength int `yaml:"min_length" env-default:"1"`
+	MaxLength int `yaml:"max_length" env-default:"64"`
+	// Lowercase запрещает заглавные буквы.
+	Lowercase bool `yaml:"lowercase"`
+	// Reserved — дополнительные зарезервированные псевдонимы. Первые сегменты путей
+	// маршрутов сервиса (api, url, metrics, ...) зарезервированы всегда.
+	Reserved []string `yaml:"reserved"`
+}
+
+// URLNormalization — необязательные шаги нормализации адресов. Схема и хост всегда
+// приводятся к нижнему регистру, домены — к punycode, а порт по умолчанию и сегменты "." и ".." убираются.
+type URLNormalization struct {
+	// SortQuery упорядочивает параметры запроса по имени.
+	SortQuery bool `yaml:"sort_query"`
+	// StripTracking удаляет отслеживающие параметры из TrackingParams.
+	StripTracking bool `yaml:"strip_tracking"`
+	// TrackingParams — удаляемые параметры, "utm_*" означает любой параметр с префиксом utm_.
+	// Пустой список — utm_*, fbclid, gclid и yclid.
+	TrackingParams []string `yaml:"tracking_params"`
 }
 
 func MustLoad() *Config {
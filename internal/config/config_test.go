@@ -0,0 +1,105 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"URLite/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+// mustLoad загружает конфигурацию из YAML с обязательной секцией http_server и дополнительным текстом extra.
+func mustLoad(t *testing.T, extra string) *config.Config {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	body := "http_server:\n  user: \"user1\"\n  password: \"pass1\"\n" + extra
+	require.NoError(t, os.WriteFile(path, []byte(body), 0o600))
+
+	t.Setenv("CONFIG_PATH", path)
+
+	return config.MustLoad()
+}
+
+func TestMustLoad_Cache(t *testing.T) {
+	cases := []struct {
+		name            string
+		yaml            string
+		wantSize        int
+		wantNegativeTTL time.Duration
+	}{
+		{
+			name:            "Defaults",
+			wantSize:        10000,
+			wantNegativeTTL: 30 * time.Second,
+		},
+		{
+			name:            "Zero falls back to defaults",
+			yaml:            "cache:\n  size: 0\n  negative_ttl: 0s\n",
+			wantSize:        10000,
+			wantNegativeTTL: 30 * time.Second,
+		},
+		{
+			name:            "Negative disables",
+			yaml:            "cache:\n  size: -1\n  negative_ttl: -1s\n",
+			wantSize:        -1,
+			wantNegativeTTL: -time.Second,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := mustLoad(t, tc.yaml)
+
+			require.Equal(t, tc.wantSize, cfg.Cache.Size)
+			require.Equal(t, tc.wantNegativeTTL, cfg.Cache.NegativeTTL)
+		})
+	}
+}
+
+func TestMustLoad_RateLimit(t *testing.T) {
+	cases := []struct {
+		name             string
+		yaml             string
+		wantWriteRate    float64
+		wantRedirectRate float64
+		wantAuthRate     float64
+	}{
+		{
+			name:             "Defaults",
+			wantWriteRate:    1,
+			wantRedirectRate: 50,
+			wantAuthRate:     0.2,
+		},
+		{
+			name:             "Zero falls back to defaults",
+			yaml:             "rate_limit:\n  write_rate: 0\n  redirect_rate: 0\n  auth_rate: 0\n",
+			wantWriteRate:    1,
+			wantRedirectRate: 50,
+			wantAuthRate:     0.2,
+		},
+		{
+			name:             "Negative disables",
+			yaml:             "rate_limit:\n  write_rate: -1\n  redirect_rate: -1\n  auth_rate: -1\n",
+			wantWriteRate:    -1,
+			wantRedirectRate: -1,
+			wantAuthRate:     -1,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := mustLoad(t, tc.yaml)
+
+			require.Equal(t, tc.wantWriteRate, cfg.RateLimit.WriteRate)
+			require.Equal(t, tc.wantRedirectRate, cfg.RateLimit.RedirectRate)
+			require.Equal(t, tc.wantAuthRate, cfg.RateLimit.AuthRate)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package create
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+
+	resp "URLite/internal/lib/api/response"
+	"URLite/internal/lib/apikey"
+	"URLite/internal/lib/logger/sl"
+	"URLite/internal/lib/validate"
+	"URLite/internal/storage"
+)
+
+type Request struct {
+	Name string `json:"name" validate:"required"`
+	// Owner — владелец ссылок, создаваемых с ключом. По умолчанию совпадает с Name.
+	// Ссылки принадлежат владельцу "key:<Owner>" и недоступны пользователю с тем же именем.
+	Owner string `json:"owner,omitempty"`
+	// Role — права клиента с этим ключом. По умолчанию storage.RoleEditor.
+	Role string `json:"role,omitempty" validate:"omitempty,oneof=admin editor viewer"`
+}
+
+// Response содержит сам ключ: он показывается только при создании и нигде не хранится.
+type Response struct {
+	resp.Response
+	ID     int64  `json:"id,omitempty"`
+	Key    string `json:"key,omitempty"`
+	Prefix string `json:"prefix,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Owner  string `json:"owner,omitempty"`
+	Role   string `json:"role,omitempty"`
+}
+
+// APIKeySaver — интерфейс для сохранения ключа доступа.
+//
+//go:generate go run github.com/vektra/mockery/v2@v2.28.2 --name=APIKeySaver
+type APIKeySaver interface {
+	SaveAPIKey(k storage.APIKey) (int64, error)
+}
+
+// New возвращает функцию-обработчик HTTP-запросов для создания ключа доступа.
+func New(log *slog.Logger, apiKeySaver APIKeySaver) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.apikey.create.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			resp.RenderError(w, r, resp.NewError(resp.CodeBadRequest, "failed to decode request"))
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if err := validate.Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+			log.Error("invalid request", sl.Err(err))
+			resp.RenderError(w, r, resp.NewValidationError(validateErr))
+			return
+		}
+
+		owner := req.Owner
+		if owner == "" {
+			owner = req.Name
+		}
+
+		role := req.Role
+		if role == "" {
+			role = storage.RoleEditor
+		}
+
+		key, err := apikey.Generate()
+		if err != nil {
+			log.Error("failed to generate api key", sl.Err(err))
+			resp.RenderError(w, r, resp.NewError(resp.CodeInternal, "internal error"))
+			return
+		}
+
+		k := storage.APIKey{
+			Name:      req.Name,
+			Owner:     owner,
+			Role:      role,
+			Prefix:    apikey.Prefix(key),
+			Hash:      apikey.Hash(key),
+			CreatedAt: time.Now().UTC(),
+		}
+
+		id, err := apiKeySaver.SaveAPIKey(k)
+		if err != nil {
+			log.Error("failed to save api key", sl.Err(err))
+			resp.RenderError(w, r, resp.NewError(resp.CodeInternal, "internal error"))
+			return
+		}
+
+		log.Info("api key created", slog.Int64("id", id), slog.String("owner", owner), slog.String("role", role))
+
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			ID:       id,
+			Key:      key,
+			Prefix:   k.Prefix,
+			Name:     k.Name,
+			Owner:    k.Owner,
+			Role:     k.Role,
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package create_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"URLite/internal/http-server/handlers/apikey/create"
+	"URLite/internal/http-server/handlers/apikey/create/mocks"
+	"URLite/internal/lib/apikey"
+	"URLite/internal/lib/logger/handlers/slogdiscard"
+	"URLite/internal/storage"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateHandler(t *testing.T) {
+	cases := []struct {
+		name      string
+		body      string
+		owner     string // Владелец, который должен быть сохранён
+		role      string // Роль, которая должна быть сохранена
+		code      int
+		respError string
+		mockError error
+	}{
+		{
+			name:  "Success",
+			body:  `{"name": "ci", "owner": "team-a", "role": "viewer"}`,
+			owner: "team-a",
+			role:  "viewer",
+			code:  http.StatusCreated,
+		},
+		{
+			name:  "Owner defaults to name",
+			body:  `{"name": "ci"}`,
+			owner: "ci",
+			role:  "editor",
+			code:  http.StatusCreated,
+		},
+		{
+			name:      "Empty name",
+			body:      `{"owner": "team-a"}`,
+			code:      http.StatusBadRequest,
+			respError: "field Name is a required field",
+		},
+		{
+			name:      "Unknown role",
+			body:      `{"name": "ci", "role": "root"}`,
+			code:      http.StatusBadRequest,
+			respError: "field Role must be one of: admin editor viewer",
+		},
+		{
+			name:      "Broken body",
+			body:      `{"name":`,
+			code:      http.StatusBadRequest,
+			respError: "failed to decode request",
+		},
+		{
+			name:      "SaveAPIKey Error",
+			body:      `{"name": "ci"}`,
+			owner:     "ci",
+			role:      "editor",
+			code:      http.StatusInternalServerError,
+			respError: "internal error",
+			mockError: errors.New("unexpected error"),
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			apiKeySaverMock := mocks.NewAPIKeySaver(t)
+
+			if tc.owner != "" {
+				apiKeySaverMock.On("SaveAPIKey", mock.MatchedBy(func(k storage.APIKey) bool {
+					return k.Owner == tc.owner && k.Role == tc.role && k.Hash != "" && !k.CreatedAt.IsZero()
+				})).Return(int64(1), tc.mockError).Once()
+			}
+
+			handler := create.New(slogdiscard.NewDiscardLogger(), apiKeySaverMock)
+
+			req, err := http.NewRequest(http.MethodPost, "/admin/keys", bytes.NewReader([]byte(tc.body)))
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.code, rr.Code)
+
+			var resp create.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tc.respError, resp.Error)
+
+			if tc.respError == "" {
+				require.True(t, strings.HasPrefix(resp.Key, resp.Prefix))
+				require.Equal(t, apikey.Prefix(resp.Key), resp.Prefix)
+				require.Equal(t, tc.owner, resp.Owner)
+				require.Equal(t, tc.role, resp.Role)
+			}
+		})
+	}
+}
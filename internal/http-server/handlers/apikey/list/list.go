@@ -0,0 +1,49 @@
+package list
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+
+	resp "URLite/internal/lib/api/response"
+	"URLite/internal/lib/logger/sl"
+	"URLite/internal/storage"
+)
+
+type Response struct {
+	resp.Response
+	Keys []storage.APIKey `json:"keys"`
+}
+
+// APIKeyLister — интерфейс для получения всех ключей доступа.
+//
+//go:generate go run github.com/vektra/mockery/v2@v2.28.2 --name=APIKeyLister
+type APIKeyLister interface {
+	ListAPIKeys() ([]storage.APIKey, error)
+}
+
+// New возвращает функцию-обработчик HTTP-запросов для получения списка ключей доступа.
+func New(log *slog.Logger, apiKeyLister APIKeyLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.apikey.list.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		keys, err := apiKeyLister.ListAPIKeys()
+		if err != nil {
+			log.Error("failed to list api keys", sl.Err(err))
+			resp.RenderError(w, r, resp.NewError(resp.CodeInternal, "internal error"))
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Keys:     keys,
+		})
+	}
+}
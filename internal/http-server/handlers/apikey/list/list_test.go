@@ -0,0 +1,64 @@
+package list_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"URLite/internal/http-server/handlers/apikey/list"
+	"URLite/internal/http-server/handlers/apikey/list/mocks"
+	"URLite/internal/lib/logger/handlers/slogdiscard"
+	"URLite/internal/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListHandler(t *testing.T) {
+	cases := []struct {
+		name      string
+		keys      []storage.APIKey
+		code      int
+		respError string
+		mockError error
+	}{
+		{
+			name: "Success",
+			keys: []storage.APIKey{{ID: 1, Name: "ci", Owner: "team-a", Prefix: "ulk_01234567", Hash: "secret"}},
+			code: http.StatusOK,
+		},
+		{
+			name:      "ListAPIKeys Error",
+			code:      http.StatusInternalServerError,
+			respError: "internal error",
+			mockError: errors.New("unexpected error"),
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			apiKeyListerMock := mocks.NewAPIKeyLister(t)
+			apiKeyListerMock.On("ListAPIKeys").Return(tc.keys, tc.mockError).Once()
+
+			handler := list.New(slogdiscard.NewDiscardLogger(), apiKeyListerMock)
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/keys", nil)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.code, rr.Code)
+			// Хеш ключа не должен попадать в ответ
+			require.NotContains(t, rr.Body.String(), "secret")
+
+			var resp list.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tc.respError, resp.Error)
+			require.Len(t, resp.Keys, len(tc.keys))
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package revoke
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+
+	resp "URLite/internal/lib/api/response"
+	"URLite/internal/lib/logger/sl"
+	"URLite/internal/storage"
+)
+
+// APIKeyRevoker — интерфейс для отзыва ключа доступа.
+//
+//go:generate go run github.com/vektra/mockery/v2@v2.28.2 --name=APIKeyRevoker
+type APIKeyRevoker interface {
+	RevokeAPIKey(id int64, at time.Time) error
+}
+
+// New возвращает функцию-обработчик HTTP-запросов для отзыва ключа доступа по идентификатору.
+func New(log *slog.Logger, apiKeyRevoker APIKeyRevoker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.apikey.revoke.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil || id <= 0 {
+			log.Info("invalid api key id", slog.String("id", chi.URLParam(r, "id")))
+			resp.RenderError(w, r, resp.NewError(resp.CodeBadRequest, "incorrect request"))
+			return
+		}
+
+		err = apiKeyRevoker.RevokeAPIKey(id, time.Now().UTC())
+		if errors.Is(err, storage.ErrAPIKeyNotFound) {
+			log.Info("api key not found", slog.Int64("id", id))
+			resp.RenderError(w, r, resp.NewError(resp.CodeNotFound, "not found"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to revoke api key", sl.Err(err))
+			resp.RenderError(w, r, resp.NewError(resp.CodeInternal, "internal error"))
+			return
+		}
+
+		log.Info("api key revoked", slog.Int64("id", id))
+
+		render.JSON(w, r, resp.OK())
+	}
+}
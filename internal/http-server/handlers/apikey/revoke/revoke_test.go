@@ -0,0 +1,86 @@
+package revoke_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"URLite/internal/http-server/handlers/apikey/revoke"
+	"URLite/internal/http-server/handlers/apikey/revoke/mocks"
+	"URLite/internal/lib/api/response"
+	"URLite/internal/lib/logger/handlers/slogdiscard"
+	"URLite/internal/storage"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevokeHandler(t *testing.T) {
+	cases := []struct {
+		name      string
+		id        string
+		mockID    int64
+		code      int
+		respError string
+		mockError error
+	}{
+		{
+			name:   "Success",
+			id:     "7",
+			mockID: 7,
+			code:   http.StatusOK,
+		},
+		{
+			name:      "Invalid ID",
+			id:        "abc",
+			code:      http.StatusBadRequest,
+			respError: "incorrect request",
+		},
+		{
+			name:      "Key Not Found",
+			id:        "42",
+			mockID:    42,
+			code:      http.StatusNotFound,
+			respError: "not found",
+			mockError: storage.ErrAPIKeyNotFound,
+		},
+		{
+			name:      "RevokeAPIKey Error",
+			id:        "7",
+			mockID:    7,
+			code:      http.StatusInternalServerError,
+			respError: "internal error",
+			mockError: errors.New("unexpected error"),
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			apiKeyRevokerMock := mocks.NewAPIKeyRevoker(t)
+
+			if tc.mockID != 0 {
+				apiKeyRevokerMock.On("RevokeAPIKey", tc.mockID, mock.AnythingOfType("time.Time")).Return(tc.mockError).Once()
+			}
+
+			r := chi.NewRouter()
+			r.Delete("/admin/keys/{id}", revoke.New(slogdiscard.NewDiscardLogger(), apiKeyRevokerMock))
+
+			req := httptest.NewRequest(http.MethodDelete, "/admin/keys/"+tc.id, nil)
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.code, rr.Code)
+
+			var resp response.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tc.respError, resp.Error)
+		})
+	}
+}
@@ -9,19 +9,23 @@ import (
 	"github.com/go-chi/render"
 	"log/slog"
 
+	"URLite/internal/http-server/middleware/auth"
 	resp "URLite/internal/lib/api/response"
 	"URLite/internal/lib/logger/sl"
 	"URLite/internal/storage"
 )
 
 // URLDeleter — интерфейс для удаления URL по псевдониму.
+// GetURLInfo нужен, чтобы проверить владельца ссылки перед удалением.
 //
 //go:generate go run github.com/vektra/mockery/v2@v2.28.2 --name=URLDeleter
 type URLDeleter interface {
+	GetURLInfo(alias string) (storage.URL, error)
 	DeleteURL(alias string) error
 }
 
 // New возвращает функцию-обработчик HTTP-запросов для удаления URL по псевдониму.
+// Удалить ссылку может только её владелец или администратор.
 func New(log *slog.Logger, urlDeleter URLDeleter) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.url.delete.New"
@@ -36,21 +40,40 @@ func New(log *slog.Logger, urlDeleter URLDeleter) http.HandlerFunc {
 		alias := chi.URLParam(r, "alias")
 		if alias == "" {
 			log.Info("empty alias")
-			render.JSON(w, r, resp.Error("incorrect request"))
+			resp.RenderError(w, r, resp.NewError(resp.CodeBadRequest, "incorrect request"))
+			return
+		}
+
+		u, err := urlDeleter.GetURLInfo(alias)
+		if errors.Is(err, storage.ErrURLNotFound) {
+			log.Info("url not found", slog.String("alias", alias))
+			resp.RenderError(w, r, resp.NewError(resp.CodeNotFound, "not found"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to get URL", sl.Err(err))
+			resp.RenderError(w, r, resp.NewError(resp.CodeInternal, "internal error"))
+			return
+		}
+
+		// Проверяем, что клиент владеет ссылкой
+		principal, _ := auth.PrincipalFromContext(r.Context())
+		if !principal.CanManage(u.Owner) {
+			log.Info("url is owned by another client", slog.String("alias", alias), slog.String("owner", u.Owner))
+			resp.RenderError(w, r, resp.NewError(resp.CodeForbidden, "forbidden"))
 			return
 		}
 
 		// Пытаемся удалить URL по псевдониму
-		err := urlDeleter.DeleteURL(alias)
+		err = urlDeleter.DeleteURL(alias)
 		if errors.Is(err, storage.ErrURLNotFound) {
 			log.Info("url not found", slog.String("alias", alias))
-			render.JSON(w, r, resp.Error("not found"))
-			w.WriteHeader(http.StatusNotFound)
+			resp.RenderError(w, r, resp.NewError(resp.CodeNotFound, "not found"))
 			return
 		}
 		if err != nil {
 			log.Error("failed to delete URL", sl.Err(err))
-			render.JSON(w, r, resp.Error("internal error"))
+			resp.RenderError(w, r, resp.NewError(resp.CodeInternal, "internal error"))
 			return
 		}
 
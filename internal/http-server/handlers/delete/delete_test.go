@@ -1,13 +1,17 @@
 package delete_test
 
 import (
+	"errors"
+	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"URLite/internal/http-server/handlers/delete"
 	"URLite/internal/http-server/handlers/delete/mocks"
+	"URLite/internal/http-server/middleware/auth"
 	"URLite/internal/lib/api"
 	"URLite/internal/lib/logger/handlers/slogdiscard"
+	"URLite/internal/storage"
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -17,12 +21,16 @@ func TestDeleteHandler(t *testing.T) {
 	cases := []struct {
 		name      string
 		alias     string
+		owner     string         // Владелец удаляемой ссылки
+		principal auth.Principal // Клиент, выполняющий запрос
+		delete    bool           // Должно ли быть вызвано удаление
 		respError string
 		mockError error
 	}{
 		{
-			name:  "Success",
-			alias: "test_alias",
+			name:   "Success",
+			alias:  "test_alias",
+			delete: true,
 		},
 		{
 			name:      "Empty Alias",
@@ -30,12 +38,43 @@ func TestDeleteHandler(t *testing.T) {
 			respError: "api.DeleteURL: invalid status code: 404", // Проверка на 400 статус
 		},
 		{
-			name:  "URL Not Found",
-			alias: "nonexistent_alias",
+			name:      "URL Not Found",
+			alias:     "nonexistent_alias",
+			delete:    true,
+			respError: "api.DeleteURL: invalid status code: 404",
+			mockError: storage.ErrURLNotFound,
 		},
 		{
-			name:  "Internal Server Error",
-			alias: "error_alias",
+			name:      "Internal Server Error",
+			alias:     "error_alias",
+			delete:    true,
+			respError: "api.DeleteURL: invalid status code: 500",
+			mockError: errors.New("unexpected error"),
+		},
+		{
+			name:      "Admin deletes foreign URL",
+			alias:     "test_alias",
+			owner:     "team-b",
+			principal: auth.Principal{Owner: "admin", Role: storage.RoleAdmin},
+			delete:    true,
+		},
+		{
+			name:      "Foreign URL",
+			alias:     "test_alias",
+			owner:     "team-b",
+			respError: "api.DeleteURL: invalid status code: 403",
+		},
+		{
+			name:      "Viewer deletes own URL",
+			alias:     "test_alias",
+			principal: auth.Principal{Owner: "team-a", Role: storage.RoleViewer},
+			respError: "api.DeleteURL: invalid status code: 403",
+		},
+		{
+			name:      "API key of user with the same name",
+			alias:     "test_alias",
+			principal: auth.Principal{Owner: "key:team-a", Role: storage.RoleEditor, APIKeyID: 7},
+			respError: "api.DeleteURL: invalid status code: 403",
 		},
 	}
 
@@ -43,11 +82,27 @@ func TestDeleteHandler(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			urlDeleterMock := mocks.NewURLDeleter(t)
 
+			owner, principal := tc.owner, tc.principal
+			if owner == "" {
+				owner = "team-a"
+			}
+			if principal == (auth.Principal{}) {
+				principal = auth.Principal{Owner: "team-a", Role: storage.RoleEditor}
+			}
+
 			if tc.alias != "" {
-				urlDeleterMock.On("DeleteURL", tc.alias).Return(tc.mockError).Once()
+				urlDeleterMock.On("GetURLInfo", tc.alias).Return(storage.URL{Alias: tc.alias, Owner: owner}, nil).Once()
+				if tc.delete {
+					urlDeleterMock.On("DeleteURL", tc.alias).Return(tc.mockError).Once()
+				}
 			}
 
 			r := chi.NewRouter()
+			r.Use(func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+				})
+			})
 			r.Delete("/{alias}", delete.New(slogdiscard.NewDiscardLogger(), urlDeleterMock))
 
 			ts := httptest.NewServer(r)
@@ -2,7 +2,11 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	storage "URLite/internal/storage"
+
+	mock "github.com/stretchr/testify/mock"
+)
 
 // URLDeleter is an autogenerated mock type for the URLDeleter type
 type URLDeleter struct {
@@ -23,6 +27,30 @@ func (_m *URLDeleter) DeleteURL(alias string) error {
 	return r0
 }
 
+// GetURLInfo provides a mock function with given fields: alias
+func (_m *URLDeleter) GetURLInfo(alias string) (storage.URL, error) {
+	ret := _m.Called(alias)
+
+	var r0 storage.URL
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (storage.URL, error)); ok {
+		return rf(alias)
+	}
+	if rf, ok := ret.Get(0).(func(string) storage.URL); ok {
+		r0 = rf(alias)
+	} else {
+		r0 = ret.Get(0).(storage.URL)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(alias)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewURLDeleter interface {
 	mock.TestingT
 	Cleanup(func())
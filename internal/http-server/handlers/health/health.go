@@ -0,0 +1,114 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+
+	resp "URLite/internal/lib/api/response"
+	"URLite/internal/lib/logger/sl"
+)
+
+// checkTimeout ограничивает время проверки всех зависимостей.
+const checkTimeout = 2 * time.Second
+
+// Checker проверяет доступность одной зависимости сервиса.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc позволяет использовать обычную функцию как Checker.
+type CheckerFunc func(ctx context.Context) error
+
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// State хранит признак остановки сервиса: после вызова Shutdown
+// сервис перестаёт считаться готовым принимать трафик.
+type State struct {
+	shuttingDown atomic.Bool
+}
+
+func (s *State) Shutdown() {
+	s.shuttingDown.Store(true)
+}
+
+func (s *State) ShuttingDown() bool {
+	return s.shuttingDown.Load()
+}
+
+// CheckResult — результат проверки одной зависимости.
+type CheckResult struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type Response struct {
+	resp.Response
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+// NewLiveness возвращает обработчик, который отвечает, пока процесс жив.
+func NewLiveness() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		render.JSON(w, r, Response{Response: resp.OK()})
+	}
+}
+
+// NewReadiness возвращает обработчик, который проверяет все зависимости сервиса
+// и отвечает 503, если хотя бы одна недоступна или сервис останавливается.
+func NewReadiness(log *slog.Logger, state *State, checks map[string]Checker) http.HandlerFunc {
+	names := make([]string, 0, len(checks))
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.health.NewReadiness"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+		defer cancel()
+
+		ready := true
+		results := make(map[string]CheckResult, len(checks)+1)
+
+		for _, name := range names {
+			if err := checks[name].Check(ctx); err != nil {
+				log.Warn("dependency is not ready", slog.String("dependency", name), sl.Err(err))
+
+				ready = false
+				results[name] = CheckResult{Status: resp.StatusError, Error: err.Error()}
+				continue
+			}
+
+			results[name] = CheckResult{Status: resp.StatusOK}
+		}
+
+		if state.ShuttingDown() {
+			ready = false
+			results["shutdown"] = CheckResult{Status: resp.StatusError, Error: "service is shutting down"}
+		}
+
+		if !ready {
+			apiErr := resp.NewError(resp.CodeUnavailable, "not ready")
+			render.Status(r, apiErr.Code.HTTPStatus())
+			render.JSON(w, r, Response{Response: apiErr.Response(), Checks: results})
+			return
+		}
+
+		render.JSON(w, r, Response{Response: resp.OK(), Checks: results})
+	}
+}
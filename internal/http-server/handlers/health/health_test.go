@@ -0,0 +1,93 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"URLite/internal/http-server/handlers/health"
+	"URLite/internal/lib/logger/handlers/slogdiscard"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLivenessHandler(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	rr := httptest.NewRecorder()
+
+	health.NewLiveness().ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `{"status":"OK"}`, rr.Body.String())
+}
+
+func TestReadinessHandler(t *testing.T) {
+	ok := health.CheckerFunc(func(context.Context) error { return nil })
+	failing := health.CheckerFunc(func(context.Context) error { return errors.New("connection refused") })
+
+	cases := []struct {
+		name         string
+		checks       map[string]health.Checker
+		shuttingDown bool
+		code         int
+		want         map[string]health.CheckResult
+	}{
+		{
+			name:   "Ready",
+			checks: map[string]health.Checker{"storage": ok, "migrations": ok},
+			code:   http.StatusOK,
+			want: map[string]health.CheckResult{
+				"storage":    {Status: "OK"},
+				"migrations": {Status: "OK"},
+			},
+		},
+		{
+			name:   "Dependency Unavailable",
+			checks: map[string]health.Checker{"storage": failing, "migrations": ok},
+			code:   http.StatusServiceUnavailable,
+			want: map[string]health.CheckResult{
+				"storage":    {Status: "Error", Error: "connection refused"},
+				"migrations": {Status: "OK"},
+			},
+		},
+		{
+			name:         "Shutting Down",
+			checks:       map[string]health.Checker{"storage": ok},
+			shuttingDown: true,
+			code:         http.StatusServiceUnavailable,
+			want: map[string]health.CheckResult{
+				"storage":  {Status: "OK"},
+				"shutdown": {Status: "Error", Error: "service is shutting down"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			state := &health.State{}
+			if tc.shuttingDown {
+				state.Shutdown()
+			}
+
+			handler := health.NewReadiness(slogdiscard.NewDiscardLogger(), state, tc.checks)
+
+			req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.code, rr.Code)
+
+			var resp health.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tc.want, resp.Checks)
+		})
+	}
+}
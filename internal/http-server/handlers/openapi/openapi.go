@@ -0,0 +1,25 @@
+package openapi
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// spec — спецификация OpenAPI 3 всех маршрутов сервиса.
+// При добавлении маршрута её нужно дополнить, иначе не пройдёт тест маршрутизатора в cmd/URLite.
+//
+//go:embed openapi.json
+var spec []byte
+
+// Spec возвращает спецификацию OpenAPI 3 в формате JSON.
+func Spec() []byte {
+	return spec
+}
+
+// New возвращает обработчик, который отдаёт спецификацию OpenAPI.
+func New() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(spec)
+	}
+}
@@ -3,13 +3,15 @@ package redirect
 import (
 	resp "URLite/internal/lib/api/response"
 	"errors"
-	"github.com/go-chi/render"
 	"net/http"
+	"net/netip"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"log/slog"
 
+	"URLite/internal/http-server/middleware/ratelimit"
 	"URLite/internal/lib/logger/sl"
 	"URLite/internal/storage"
 )
@@ -21,7 +23,17 @@ type URLGetter interface {
 	GetURL(alias string) (string, error)
 }
 
-func New(log *slog.Logger, urlGetter URLGetter) http.HandlerFunc {
+//go:generate go run github.com/vektra/mockery/v2@v2.28.2 --name=ClickRecorder
+
+// ClickRecorder — интерфейс для учёта переходов по ссылкам.
+// Реализация не должна блокировать обработку запроса.
+type ClickRecorder interface {
+	RecordClick(click storage.Click)
+}
+
+// New возвращает обработчик редиректа. Адрес клиента для учёта перехода берётся
+// из X-Forwarded-For, только если запрос пришёл от прокси из trustedProxies.
+func New(log *slog.Logger, urlGetter URLGetter, clickRecorder ClickRecorder, trustedProxies []netip.Prefix) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.url.redirect.New"
 
@@ -34,28 +46,43 @@ func New(log *slog.Logger, urlGetter URLGetter) http.HandlerFunc {
 		if alias == "" {
 			log.Info("alias is empty")
 
-			render.JSON(w, r, resp.Error("invalid request"))
+			resp.RenderError(w, r, resp.NewError(resp.CodeBadRequest, "invalid request"))
 
 			return
 		}
 
 		resURL, err := urlGetter.GetURL(alias)
+		if errors.Is(err, storage.ErrURLExpired) {
+			log.Info("url expired", "alias", alias)
+			resp.RenderError(w, r, resp.NewError(resp.CodeGone, "url expired"))
+
+			return
+		}
 		if errors.Is(err, storage.ErrURLNotFound) {
 			log.Info("url not found", "alias", alias)
-			render.JSON(w, r, resp.Error("not found"))
+			resp.RenderError(w, r, resp.NewError(resp.CodeNotFound, "not found"))
 
 			return
 		}
 		if err != nil {
 			log.Error("failed to get url", sl.Err(err))
 
-			render.JSON(w, r, resp.Error("internal error"))
+			resp.RenderError(w, r, resp.NewError(resp.CodeInternal, "internal error"))
 
 			return
 		}
 
 		log.Info("got url", slog.String("url", resURL))
 
+		clickRecorder.RecordClick(storage.Click{
+			Alias:     alias,
+			Timestamp: time.Now().UTC(),
+			Referrer:  r.Referer(),
+			UserAgent: r.UserAgent(),
+			RemoteIP:  ratelimit.ClientIP(r, trustedProxies),
+			RequestID: middleware.GetReqID(r.Context()),
+		})
+
 		// redirect to found url
 		http.Redirect(w, r, resURL, http.StatusFound)
 	}
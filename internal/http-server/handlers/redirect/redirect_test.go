@@ -2,7 +2,9 @@ package redirect_test
 
 import (
 	"errors"
+	"net/http"
 	"net/http/httptest"
+	"net/netip"
 	"testing"
 
 	"URLite/internal/http-server/handlers/redirect"
@@ -11,7 +13,9 @@ import (
 	"URLite/internal/lib/logger/handlers/slogdiscard"
 	"URLite/internal/storage"
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -36,13 +40,19 @@ func TestRedirectHandler(t *testing.T) {
 		{
 			name:      "URL Not Found",
 			alias:     "nonexistent_alias",
-			respError: "api.GetRedirect: invalid status code: 200",
+			respError: "api.GetRedirect: invalid status code: 404",
 			mockError: storage.ErrURLNotFound,
 		},
+		{
+			name:      "URL Expired",
+			alias:     "expired_alias",
+			respError: "api.GetRedirect: invalid status code: 410",
+			mockError: storage.ErrURLExpired,
+		},
 		{
 			name:      "Internal Server Error",
 			alias:     "error_alias",
-			respError: "api.GetRedirect: invalid status code: 200",
+			respError: "api.GetRedirect: invalid status code: 500",
 			mockError: errors.New("internal error"),
 		},
 	}
@@ -50,13 +60,22 @@ func TestRedirectHandler(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			urlGetterMock := mocks.NewURLGetter(t)
+			clickRecorderMock := mocks.NewClickRecorder(t)
 
 			if tc.alias != "" {
 				urlGetterMock.On("GetURL", tc.alias).Return(tc.url, tc.mockError).Once()
 			}
 
+			// Переход должен учитываться только при успешном редиректе
+			if tc.respError == "" {
+				clickRecorderMock.On("RecordClick", mock.MatchedBy(func(c storage.Click) bool {
+					return c.Alias == tc.alias && c.RemoteIP == "127.0.0.1" && c.RequestID != "" && !c.Timestamp.IsZero()
+				})).Once()
+			}
+
 			r := chi.NewRouter()
-			r.Get("/{alias}", redirect.New(slogdiscard.NewDiscardLogger(), urlGetterMock))
+			r.Use(middleware.RequestID)
+			r.Get("/{alias}", redirect.New(slogdiscard.NewDiscardLogger(), urlGetterMock, clickRecorderMock, nil))
 
 			ts := httptest.NewServer(r)
 			defer ts.Close()
@@ -73,3 +92,61 @@ func TestRedirectHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestRedirectHandler_ClientIP(t *testing.T) {
+	trustedProxies := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+
+	cases := []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		ip         string
+	}{
+		{
+			name:       "Direct client",
+			remoteAddr: "203.0.113.7:4321",
+			ip:         "203.0.113.7",
+		},
+		{
+			name:       "Behind trusted proxy",
+			remoteAddr: "10.0.0.1:4321",
+			forwarded:  "198.51.100.2, 10.0.0.2",
+			ip:         "198.51.100.2",
+		},
+		{
+			name:       "Forwarded header from untrusted client",
+			remoteAddr: "203.0.113.7:4321",
+			forwarded:  "198.51.100.2",
+			ip:         "203.0.113.7",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			urlGetterMock := mocks.NewURLGetter(t)
+			clickRecorderMock := mocks.NewClickRecorder(t)
+
+			urlGetterMock.On("GetURL", "test_alias").Return("https://go.dev/", nil).Once()
+			clickRecorderMock.On("RecordClick", mock.MatchedBy(func(c storage.Click) bool {
+				return c.RemoteIP == tc.ip
+			})).Once()
+
+			r := chi.NewRouter()
+			r.Get("/{alias}", redirect.New(slogdiscard.NewDiscardLogger(), urlGetterMock, clickRecorderMock, trustedProxies))
+
+			req := httptest.NewRequest(http.MethodGet, "/test_alias", nil)
+			req.RemoteAddr = tc.remoteAddr
+			if tc.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tc.forwarded)
+			}
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, http.StatusFound, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package stats
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+
+	resp "URLite/internal/lib/api/response"
+	"URLite/internal/lib/logger/sl"
+	"URLite/internal/lib/useragent"
+	"URLite/internal/storage"
+)
+
+const (
+	defaultRange = 7 * 24 * time.Hour
+	defaultTop   = 10
+	maxTop       = 100
+	// maxBuckets ограничивает размер временного ряда в ответе.
+	maxBuckets = 1000
+)
+
+type Response struct {
+	resp.Response
+	Alias          string                `json:"alias,omitempty"`
+	From           time.Time             `json:"from"`
+	To             time.Time             `json:"to"`
+	Interval       string                `json:"interval,omitempty"`
+	TotalClicks    int64                 `json:"total_clicks"`
+	UniqueVisitors int64                 `json:"unique_visitors"`
+	Series         []storage.StatsBucket `json:"series,omitempty"`
+	TopReferrers   []storage.StatsCount  `json:"top_referrers,omitempty"`
+	TopUserAgents  []storage.StatsCount  `json:"top_user_agents,omitempty"`
+}
+
+// StatsGetter — интерфейс для получения статистики переходов по ссылке.
+//
+//go:generate go run github.com/vektra/mockery/v2@v2.28.2 --name=StatsGetter
+type StatsGetter interface {
+	GetURLStats(alias string, q storage.StatsQuery) (storage.URLStats, error)
+}
+
+// New возвращает функцию-обработчик HTTP-запросов для получения статистики переходов.
+//
+// Поддерживаемые параметры запроса: from и to (RFC 3339, по умолчанию — последние 7 дней),
+// interval (hour, day) и top — размер списков самых частых источников и браузеров.
+func New(log *slog.Logger, statsGetter StatsGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.stats.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		alias := chi.URLParam(r, "alias")
+		if alias == "" {
+			log.Info("empty alias")
+			resp.RenderError(w, r, resp.NewError(resp.CodeBadRequest, "incorrect request"))
+			return
+		}
+
+		q, err := parseQuery(r, time.Now())
+		if err != nil {
+			log.Info("invalid query", sl.Err(err))
+			resp.RenderError(w, r, resp.NewError(resp.CodeBadRequest, err.Error()))
+			return
+		}
+
+		stats, err := statsGetter.GetURLStats(alias, q)
+		if errors.Is(err, storage.ErrURLNotFound) {
+			log.Info("url not found", slog.String("alias", alias))
+			resp.RenderError(w, r, resp.NewError(resp.CodeNotFound, "not found"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to get stats", sl.Err(err))
+			resp.RenderError(w, r, resp.NewError(resp.CodeInternal, "internal error"))
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response:       resp.OK(),
+			Alias:          alias,
+			From:           q.From,
+			To:             q.To,
+			Interval:       q.Interval,
+			TotalClicks:    stats.TotalClicks,
+			UniqueVisitors: stats.UniqueVisitors,
+			Series:         fillSeries(stats.Series, q),
+			TopReferrers:   stats.TopReferrers,
+			TopUserAgents:  topFamilies(stats.UserAgents, q.Top),
+		})
+	}
+}
+
+func parseQuery(r *http.Request, now time.Time) (storage.StatsQuery, error) {
+	query := r.URL.Query()
+
+	q := storage.StatsQuery{
+		To:       now.UTC(),
+		Interval: storage.IntervalDay,
+		Top:      defaultTop,
+	}
+
+	if to := query.Get("to"); to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return q, errors.New("to must be an RFC 3339 timestamp")
+		}
+		q.To = t.UTC()
+	}
+
+	q.From = q.To.Add(-defaultRange)
+	if from := query.Get("from"); from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return q, errors.New("from must be an RFC 3339 timestamp")
+		}
+		q.From = t.UTC()
+	}
+
+	if !q.From.Before(q.To) {
+		return q, errors.New("from must be before to")
+	}
+
+	switch interval := query.Get("interval"); interval {
+	case "", storage.IntervalDay:
+	case storage.IntervalHour:
+		q.Interval = storage.IntervalHour
+	default:
+		return q, errors.New("interval must be one of: hour, day")
+	}
+
+	step := time.Duration(storage.IntervalSeconds(q.Interval)) * time.Second
+	if q.To.Sub(q.From)/step > maxBuckets {
+		return q, errors.New("time range is too large for the interval")
+	}
+
+	if top := query.Get("top"); top != "" {
+		n, err := strconv.Atoi(top)
+		if err != nil || n < 1 || n > maxTop {
+			return q, errors.New("top must be between 1 and " + strconv.Itoa(maxTop))
+		}
+		q.Top = n
+	}
+
+	return q, nil
+}
+
+// fillSeries дополняет временной ряд нулями для интервалов без переходов.
+func fillSeries(series []storage.StatsBucket, q storage.StatsQuery) []storage.StatsBucket {
+	step := storage.IntervalSeconds(q.Interval)
+
+	clicks := make(map[int64]int64, len(series))
+	for _, b := range series {
+		clicks[b.Start.Unix()] = b.Clicks
+	}
+
+	var filled []storage.StatsBucket
+	for start := q.From.Unix() / step * step; start < q.To.Unix(); start += step {
+		filled = append(filled, storage.StatsBucket{
+			Start:  time.Unix(start, 0).UTC(),
+			Clicks: clicks[start],
+		})
+	}
+
+	return filled
+}
+
+// topFamilies группирует переходы по семействам браузеров и возвращает n самых частых.
+func topFamilies(userAgents []storage.StatsCount, n int) []storage.StatsCount {
+	counts := make(map[string]int64)
+	for _, ua := range userAgents {
+		counts[useragent.Family(ua.Value)] += ua.Count
+	}
+
+	families := make([]storage.StatsCount, 0, len(counts))
+	for family, count := range counts {
+		families = append(families, storage.StatsCount{Value: family, Count: count})
+	}
+
+	sort.Slice(families, func(i, j int) bool {
+		if families[i].Count != families[j].Count {
+			return families[i].Count > families[j].Count
+		}
+		return families[i].Value < families[j].Value
+	})
+
+	if len(families) > n {
+		families = families[:n]
+	}
+
+	return families
+}
@@ -0,0 +1,160 @@
+package stats_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"URLite/internal/http-server/handlers/stats"
+	"URLite/internal/http-server/handlers/stats/mocks"
+	"URLite/internal/lib/logger/handlers/slogdiscard"
+	"URLite/internal/storage"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	chromeUA  = "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36"
+	firefoxUA = "Mozilla/5.0 (X11; Linux x86_64; rv:127.0) Gecko/20100101 Firefox/127.0"
+)
+
+func TestStatsHandler(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC)
+
+	urlStats := storage.URLStats{
+		TotalClicks:    6,
+		UniqueVisitors: 3,
+		Series: []storage.StatsBucket{
+			{Start: from.Add(24 * time.Hour), Clicks: 6},
+		},
+		TopReferrers: []storage.StatsCount{{Value: "https://go.dev/", Count: 4}},
+		UserAgents: []storage.StatsCount{
+			{Value: firefoxUA, Count: 3},
+			{Value: chromeUA + " v1", Count: 1},
+			{Value: chromeUA + " v2", Count: 1},
+			{Value: "curl/8.5.0", Count: 1},
+		},
+	}
+
+	query := storage.StatsQuery{From: from, To: to, Interval: storage.IntervalDay, Top: 2}
+
+	cases := []struct {
+		name      string
+		alias     string
+		query     string
+		code      int
+		respError string
+		mockError error
+		callMock  bool
+	}{
+		{
+			name:     "Success",
+			alias:    "test_alias",
+			query:    "?from=2024-01-01T00:00:00Z&to=2024-01-04T00:00:00Z&interval=day&top=2",
+			code:     http.StatusOK,
+			callMock: true,
+		},
+		{
+			name:      "Invalid from",
+			alias:     "test_alias",
+			query:     "?from=yesterday",
+			code:      http.StatusBadRequest,
+			respError: "from must be an RFC 3339 timestamp",
+		},
+		{
+			name:      "Empty range",
+			alias:     "test_alias",
+			query:     "?from=2024-01-04T00:00:00Z&to=2024-01-01T00:00:00Z",
+			code:      http.StatusBadRequest,
+			respError: "from must be before to",
+		},
+		{
+			name:      "Invalid interval",
+			alias:     "test_alias",
+			query:     "?interval=week",
+			code:      http.StatusBadRequest,
+			respError: "interval must be one of: hour, day",
+		},
+		{
+			name:      "Range too large",
+			alias:     "test_alias",
+			query:     "?from=2020-01-01T00:00:00Z&to=2024-01-01T00:00:00Z&interval=hour",
+			code:      http.StatusBadRequest,
+			respError: "time range is too large for the interval",
+		},
+		{
+			name:      "URL Not Found",
+			alias:     "nonexistent_alias",
+			query:     "?from=2024-01-01T00:00:00Z&to=2024-01-04T00:00:00Z&interval=day&top=2",
+			code:      http.StatusNotFound,
+			respError: "not found",
+			mockError: storage.ErrURLNotFound,
+			callMock:  true,
+		},
+		{
+			name:      "Internal Server Error",
+			alias:     "error_alias",
+			query:     "?from=2024-01-01T00:00:00Z&to=2024-01-04T00:00:00Z&interval=day&top=2",
+			code:      http.StatusInternalServerError,
+			respError: "internal error",
+			mockError: errors.New("unexpected error"),
+			callMock:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			statsGetterMock := mocks.NewStatsGetter(t)
+
+			if tc.callMock {
+				statsGetterMock.On("GetURLStats", tc.alias, query).Return(urlStats, tc.mockError).Once()
+			}
+
+			r := chi.NewRouter()
+			r.Get("/{alias}/stats", stats.New(slogdiscard.NewDiscardLogger(), statsGetterMock))
+
+			req, err := http.NewRequest(http.MethodGet, "/"+tc.alias+"/stats"+tc.query, nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.code, rr.Code)
+
+			var resp stats.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tc.respError, resp.Error)
+
+			if tc.respError != "" {
+				return
+			}
+
+			require.EqualValues(t, 6, resp.TotalClicks)
+			require.EqualValues(t, 3, resp.UniqueVisitors)
+
+			// Пустые интервалы заполняются нулями
+			require.Equal(t, []storage.StatsBucket{
+				{Start: from, Clicks: 0},
+				{Start: from.Add(24 * time.Hour), Clicks: 6},
+				{Start: from.Add(48 * time.Hour), Clicks: 0},
+			}, resp.Series)
+
+			require.Equal(t, urlStats.TopReferrers, resp.TopReferrers)
+
+			// User-Agent группируются по семействам браузеров
+			require.Equal(t, []storage.StatsCount{
+				{Value: "Firefox", Count: 3},
+				{Value: "Chrome", Count: 2},
+			}, resp.TopUserAgents)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package info
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+
+	resp "URLite/internal/lib/api/response"
+	"URLite/internal/lib/logger/sl"
+	"URLite/internal/storage"
+)
+
+type Response struct {
+	resp.Response
+	URL *storage.URL `json:"url,omitempty"`
+}
+
+// URLInfoGetter — интерфейс для получения ссылки со всеми метаданными.
+//
+//go:generate go run github.com/vektra/mockery/v2@v2.28.2 --name=URLInfoGetter
+type URLInfoGetter interface {
+	GetURLInfo(alias string) (storage.URL, error)
+}
+
+// New возвращает функцию-обработчик HTTP-запросов для получения ссылки по псевдониму.
+func New(log *slog.Logger, urlInfoGetter URLInfoGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.info.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		alias := chi.URLParam(r, "alias")
+		if alias == "" {
+			log.Info("empty alias")
+			resp.RenderError(w, r, resp.NewError(resp.CodeBadRequest, "incorrect request"))
+			return
+		}
+
+		u, err := urlInfoGetter.GetURLInfo(alias)
+		if errors.Is(err, storage.ErrURLNotFound) {
+			log.Info("url not found", slog.String("alias", alias))
+			resp.RenderError(w, r, resp.NewError(resp.CodeNotFound, "not found"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to get url", sl.Err(err))
+			resp.RenderError(w, r, resp.NewError(resp.CodeInternal, "internal error"))
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			URL:      &u,
+		})
+	}
+}
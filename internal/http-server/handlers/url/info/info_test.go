@@ -0,0 +1,80 @@
+package info_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"URLite/internal/http-server/handlers/url/info"
+	"URLite/internal/http-server/handlers/url/info/mocks"
+	"URLite/internal/lib/logger/handlers/slogdiscard"
+	"URLite/internal/storage"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInfoHandler(t *testing.T) {
+	cases := []struct {
+		name      string
+		alias     string
+		url       storage.URL
+		code      int
+		respError string
+		mockError error
+	}{
+		{
+			name:  "Success",
+			alias: "test_alias",
+			url:   storage.URL{ID: 42, Alias: "test_alias", URL: "https://go.dev/"},
+			code:  http.StatusOK,
+		},
+		{
+			name:      "URL Not Found",
+			alias:     "nonexistent_alias",
+			code:      http.StatusNotFound,
+			respError: "not found",
+			mockError: storage.ErrURLNotFound,
+		},
+		{
+			name:      "Internal Server Error",
+			alias:     "error_alias",
+			code:      http.StatusInternalServerError,
+			respError: "internal error",
+			mockError: errors.New("unexpected error"),
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			urlInfoGetterMock := mocks.NewURLInfoGetter(t)
+			urlInfoGetterMock.On("GetURLInfo", tc.alias).Return(tc.url, tc.mockError).Once()
+
+			r := chi.NewRouter()
+			r.Get("/{alias}", info.New(slogdiscard.NewDiscardLogger(), urlInfoGetterMock))
+
+			req, err := http.NewRequest(http.MethodGet, "/"+tc.alias, nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.code, rr.Code)
+
+			var resp info.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tc.respError, resp.Error)
+
+			if tc.respError == "" {
+				require.NotNil(t, resp.URL)
+				require.Equal(t, tc.url, *resp.URL)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package list
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+
+	resp "URLite/internal/lib/api/response"
+	"URLite/internal/lib/logger/sl"
+	"URLite/internal/storage"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+type Response struct {
+	resp.Response
+	URLs       []storage.URL `json:"urls"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+// URLLister — интерфейс для постраничного получения списка ссылок.
+//
+//go:generate go run github.com/vektra/mockery/v2@v2.28.2 --name=URLLister
+type URLLister interface {
+	ListURLs(params storage.ListParams) (storage.URLPage, error)
+}
+
+// New возвращает функцию-обработчик HTTP-запросов для получения списка ссылок.
+//
+// Поддерживаемые параметры запроса: limit, cursor, sort (id, alias),
+// order (asc, desc), alias_prefix и host.
+func New(log *slog.Logger, urlLister URLLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.list.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		params, err := parseParams(r)
+		if err != nil {
+			log.Info("invalid query", sl.Err(err))
+			resp.RenderError(w, r, resp.NewError(resp.CodeBadRequest, err.Error()))
+			return
+		}
+
+		page, err := urlLister.ListURLs(params)
+		if errors.Is(err, storage.ErrInvalidCursor) {
+			log.Info("invalid cursor", slog.String("cursor", params.Cursor))
+			resp.RenderError(w, r, resp.NewError(resp.CodeBadRequest, "invalid cursor"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to list urls", sl.Err(err))
+			resp.RenderError(w, r, resp.NewError(resp.CodeInternal, "internal error"))
+			return
+		}
+
+		log.Info("urls listed", slog.Int("count", len(page.URLs)))
+
+		render.JSON(w, r, Response{
+			Response:   resp.OK(),
+			URLs:       page.URLs,
+			NextCursor: page.NextCursor,
+		})
+	}
+}
+
+func parseParams(r *http.Request) (storage.ListParams, error) {
+	q := r.URL.Query()
+
+	params := storage.ListParams{
+		Limit:       defaultLimit,
+		Cursor:      q.Get("cursor"),
+		SortBy:      storage.SortByID,
+		AliasPrefix: q.Get("alias_prefix"),
+		Host:        q.Get("host"),
+	}
+
+	if limit := q.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > maxLimit {
+			return params, errors.New("limit must be between 1 and " + strconv.Itoa(maxLimit))
+		}
+		params.Limit = n
+	}
+
+	switch sortBy := q.Get("sort"); sortBy {
+	case "", storage.SortByID:
+	case storage.SortByAlias:
+		params.SortBy = storage.SortByAlias
+	default:
+		return params, errors.New("sort must be one of: id, alias")
+	}
+
+	switch order := q.Get("order"); order {
+	case "", "asc":
+	case "desc":
+		params.Desc = true
+	default:
+		return params, errors.New("order must be one of: asc, desc")
+	}
+
+	return params, nil
+}
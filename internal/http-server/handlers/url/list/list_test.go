@@ -0,0 +1,122 @@
+package list_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"URLite/internal/http-server/handlers/url/list"
+	"URLite/internal/http-server/handlers/url/list/mocks"
+	"URLite/internal/lib/logger/handlers/slogdiscard"
+	"URLite/internal/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListHandler(t *testing.T) {
+	page := storage.URLPage{
+		URLs: []storage.URL{
+			{ID: 1, Alias: "go", URL: "https://go.dev/"},
+			{ID: 2, Alias: "google", URL: "https://www.google.com"},
+		},
+		NextCursor: "next",
+	}
+
+	cases := []struct {
+		name      string
+		query     string
+		params    *storage.ListParams // параметры, которые должны попасть в хранилище
+		code      int
+		respError string
+		mockError error
+	}{
+		{
+			name:   "Defaults",
+			query:  "",
+			params: &storage.ListParams{Limit: 20, SortBy: storage.SortByID},
+			code:   http.StatusOK,
+		},
+		{
+			name:  "All parameters",
+			query: "?limit=5&cursor=abc&sort=alias&order=desc&alias_prefix=go&host=go.dev",
+			params: &storage.ListParams{
+				Limit:       5,
+				Cursor:      "abc",
+				SortBy:      storage.SortByAlias,
+				Desc:        true,
+				AliasPrefix: "go",
+				Host:        "go.dev",
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:      "Invalid limit",
+			query:     "?limit=1000",
+			code:      http.StatusBadRequest,
+			respError: "limit must be between 1 and 100",
+		},
+		{
+			name:      "Invalid sort",
+			query:     "?sort=url",
+			code:      http.StatusBadRequest,
+			respError: "sort must be one of: id, alias",
+		},
+		{
+			name:      "Invalid order",
+			query:     "?order=random",
+			code:      http.StatusBadRequest,
+			respError: "order must be one of: asc, desc",
+		},
+		{
+			name:      "Invalid cursor",
+			query:     "?cursor=broken",
+			params:    &storage.ListParams{Limit: 20, Cursor: "broken", SortBy: storage.SortByID},
+			code:      http.StatusBadRequest,
+			respError: "invalid cursor",
+			mockError: storage.ErrInvalidCursor,
+		},
+		{
+			name:      "ListURLs Error",
+			query:     "",
+			params:    &storage.ListParams{Limit: 20, SortBy: storage.SortByID},
+			code:      http.StatusInternalServerError,
+			respError: "internal error",
+			mockError: errors.New("unexpected error"),
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			urlListerMock := mocks.NewURLLister(t)
+
+			if tc.params != nil {
+				urlListerMock.On("ListURLs", *tc.params).Return(page, tc.mockError).Once()
+			}
+
+			handler := list.New(slogdiscard.NewDiscardLogger(), urlListerMock)
+
+			req, err := http.NewRequest(http.MethodGet, "/url"+tc.query, nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.code, rr.Code)
+
+			var resp list.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tc.respError, resp.Error)
+
+			if tc.respError == "" {
+				require.Equal(t, page.URLs, resp.URLs)
+				require.Equal(t, page.NextCursor, resp.NextCursor)
+			}
+		})
+	}
+}
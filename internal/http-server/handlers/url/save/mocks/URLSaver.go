@@ -2,30 +2,72 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	storage "URLite/internal/storage"
+
+	mock "github.com/stretchr/testify/mock"
+)
 
 // URLSaver is an autogenerated mock type for the URLSaver type
 type URLSaver struct {
 	mock.Mock
 }
 
-// SaveURL provides a mock function with given fields: urlToSave, alias
-func (_m *URLSaver) SaveURL(urlToSave string, alias string) (int64, error) {
-	ret := _m.Called(urlToSave, alias)
+// DeleteURL provides a mock function with given fields: alias
+func (_m *URLSaver) DeleteURL(alias string) error {
+	ret := _m.Called(alias)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(alias)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindURL provides a mock function with given fields: owner, target
+func (_m *URLSaver) FindURL(owner string, target string) (storage.URL, error) {
+	ret := _m.Called(owner, target)
+
+	var r0 storage.URL
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (storage.URL, error)); ok {
+		return rf(owner, target)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) storage.URL); ok {
+		r0 = rf(owner, target)
+	} else {
+		r0 = ret.Get(0).(storage.URL)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(owner, target)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveURL provides a mock function with given fields: u
+func (_m *URLSaver) SaveURL(u storage.URL) (int64, error) {
+	ret := _m.Called(u)
 
 	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string) (int64, error)); ok {
-		return rf(urlToSave, alias)
+	if rf, ok := ret.Get(0).(func(storage.URL) (int64, error)); ok {
+		return rf(u)
 	}
-	if rf, ok := ret.Get(0).(func(string, string) int64); ok {
-		r0 = rf(urlToSave, alias)
+	if rf, ok := ret.Get(0).(func(storage.URL) int64); ok {
+		r0 = rf(u)
 	} else {
 		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(urlToSave, alias)
+	if rf, ok := ret.Get(1).(func(storage.URL) error); ok {
+		r1 = rf(u)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -33,6 +75,20 @@ func (_m *URLSaver) SaveURL(urlToSave string, alias string) (int64, error) {
 	return r0, r1
 }
 
+// UpdateURL provides a mock function with given fields: alias, upd
+func (_m *URLSaver) UpdateURL(alias string, upd storage.URLUpdate) error {
+	ret := _m.Called(alias, upd)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, storage.URLUpdate) error); ok {
+		r0 = rf(alias, upd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewURLSaver interface {
 	mock.TestingT
 	Cleanup(func())
@@ -1,41 +1,96 @@
 package save
 
 import (
+	"URLite/internal/http-server/middleware/auth"
+	"URLite/internal/lib/alias"
 	resp "URLite/internal/lib/api/response"
 	"URLite/internal/lib/logger/sl"
-	"URLite/internal/lib/random"
+	"URLite/internal/lib/urlnorm"
+	"URLite/internal/lib/validate"
 	"URLite/internal/storage"
 	"errors"
+	"fmt"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
 	"github.com/go-playground/validator/v10"
 	"log/slog"
 	"net/http"
+	"time"
 )
 
 type Request struct {
-	URL   string `json:"url" validate:"required,url"`
-	Alias string `json:"alias,omitempty"`
+	URL string `json:"url" validate:"required,url"`
+	// Alias проверяется по правилам alias.Rules, переданным в New.
+	Alias string `json:"alias,omitempty" validate:"omitempty,alias"`
+	// ExpiresAt — момент, после которого ссылка перестаёт работать.
+	ExpiresAt *time.Time `json:"expires_at,omitempty" validate:"omitempty,excluded_with=TTL"`
+	// TTL — время жизни ссылки в секундах, не больше 100 лет. Нельзя указывать вместе с ExpiresAt.
+	// Ограничение не даёт переполниться time.Duration.
+	TTL int64 `json:"ttl,omitempty" validate:"omitempty,gt=0,max=3153600000"`
+	// ReuseExisting возвращает уже существующую действующую ссылку клиента на тот же адрес
+	// вместо создания новой. Нельзя указывать вместе с Alias.
+	ReuseExisting bool `json:"reuse_existing,omitempty" validate:"excluded_with=Alias"`
 }
 
 type Response struct {
 	resp.Response
 	Alias string `json:"alias,omitempty"`
+	// URL — адрес ссылки в каноническом виде.
+	URL string `json:"url,omitempty"`
+	// OriginalURL — адрес в том виде, в котором его передал клиент при создании ссылки.
+	OriginalURL string     `json:"original_url,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
 }
 
-// TODO: move to config
-const aliasLength = 6
+// maxAliasAttempts — сколько раз генерировать псевдоним, если сгенерированный уже занят.
+const maxAliasAttempts = 5
 
 //go:generate go run github.com/vektra/mockery/v2@v2.28.2 --name=URLSaver
 type URLSaver interface {
-	SaveURL(urlToSave string, alias string) (int64, error)
+	SaveURL(u storage.URL) (int64, error)
+	FindURL(owner, target string) (storage.URL, error)
+	UpdateURL(alias string, upd storage.URLUpdate) error
+	DeleteURL(alias string) error
+}
+
+// AliasGenerator — генератор псевдонимов для ссылок, сохраняемых без псевдонима.
+//
+//go:generate go run github.com/vektra/mockery/v2@v2.28.2 --name=AliasGenerator
+type AliasGenerator interface {
+	Generate() (string, error)
+	// ReportCollision сообщает, что сгенерированный псевдоним уже занят.
+	ReportCollision()
+}
+
+// IDAliasGenerator — генератор, который строит псевдоним из идентификатора сохранённой ссылки.
+// Его Generate возвращает временный псевдоним, который заменяется после сохранения ссылки.
+//
+//go:generate go run github.com/vektra/mockery/v2@v2.28.2 --name=IDAliasGenerator
+type IDAliasGenerator interface {
+	AliasGenerator
+	FromID(id int64) (string, error)
 }
 
-func New(log *slog.Logger, urlSaver URLSaver) http.HandlerFunc {
+// New возвращает функцию-обработчик HTTP-запросов для сохранения ссылки.
+// Если псевдоним не указан, он генерируется aliasGenerator; при совпадении
+// с существующим или запрещённым aliasRules псевдонимом генерируется новый.
+// Псевдоним, заданный клиентом, проверяется по aliasRules.
+// Адрес сохраняется в виде, приведённом normalizer, а исходный адрес — рядом с ним.
+// Новая ссылка возвращается со статусом 201, а найденная при ReuseExisting — со статусом 200.
+func New(
+	log *slog.Logger,
+	urlSaver URLSaver,
+	aliasGenerator AliasGenerator,
+	aliasRules *alias.Rules,
+	normalizer *urlnorm.Normalizer,
+) http.HandlerFunc {
+	v := validate.New()
+	aliasRules.Register(v)
+
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.url.New"
 
-		log = log.With(
+		log := log.With(
 			slog.String("op", op),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
@@ -45,46 +100,173 @@ func New(log *slog.Logger, urlSaver URLSaver) http.HandlerFunc {
 		err := render.DecodeJSON(r.Body, &req)
 		if err != nil {
 			log.Error("failed to decode request body", sl.Err(err))
-			render.JSON(w, r, resp.Error("failed to decode request"))
+			resp.RenderError(w, r, resp.NewError(resp.CodeBadRequest, "failed to decode request"))
 			return
 		}
 
 		log.Info("request body decoded", slog.Any("request", req))
 
-		if err := validator.New().Struct(req); err != nil {
+		if err := v.Struct(req); err != nil {
 			validateErr := err.(validator.ValidationErrors)
 			log.Error("invalid request", sl.Err(err))
-			render.JSON(w, r, resp.ValidationError(validateErr))
+			resp.RenderError(w, r, resp.NewValidationError(validateErr))
 			return
 		}
 
-		alias := req.Alias
-		if alias == "" {
-			alias = random.NewRandomString(aliasLength)
+		target, err := normalizer.Normalize(req.URL)
+		if err != nil {
+			log.Info("failed to normalize url", sl.Err(err))
+			resp.RenderError(w, r, resp.NewError(resp.CodeBadRequest, "field URL is not a valid URL"))
+			return
 		}
 
-		id, err := urlSaver.SaveURL(req.URL, alias)
+		expiresAt, err := expiration(req, time.Now())
+		if err != nil {
+			log.Info("invalid expiration", sl.Err(err))
+			resp.RenderError(w, r, resp.NewError(resp.CodeBadRequest, err.Error()))
+			return
+		}
+
+		// Ссылка принадлежит клиенту, который её создал
+		principal, _ := auth.PrincipalFromContext(r.Context())
+
+		if req.ReuseExisting {
+			existing, err := urlSaver.FindURL(principal.Owner, target)
+			if err == nil {
+				log.Info("existing url reused", slog.String("alias", existing.Alias))
+				responseOK(w, r, http.StatusOK, existing)
+				return
+			}
+			if !errors.Is(err, storage.ErrURLNotFound) {
+				log.Error("failed to find existing url", sl.Err(err))
+				resp.RenderError(w, r, resp.NewError(resp.CodeInternal, "failed to add url"))
+				return
+			}
+		}
+
+		u := storage.URL{
+			Alias:       req.Alias,
+			URL:         target,
+			OriginalURL: req.URL,
+			Owner:       principal.Owner,
+			ExpiresAt:   expiresAt,
+		}
+
+		generated := req.Alias == ""
+
+		var id int64
+		for attempt := 1; ; attempt++ {
+			if generated {
+				u.Alias, err = aliasGenerator.Generate()
+				if err != nil {
+					log.Error("failed to generate alias", sl.Err(err))
+					resp.RenderError(w, r, resp.NewError(resp.CodeInternal, "failed to add url"))
+					return
+				}
+			}
+
+			if generated && !aliasRules.Allowed(u.Alias) {
+				// Зарезервированный псевдоним обрабатывается так же, как занятый
+				err = storage.ErrURLExists
+			} else {
+				id, err = urlSaver.SaveURL(u)
+			}
+			if !generated || !errors.Is(err, storage.ErrURLExists) {
+				break
+			}
+
+			aliasGenerator.ReportCollision()
+			log.Info("generated alias already exists", slog.String("alias", u.Alias), slog.Int("attempt", attempt))
+
+			if attempt == maxAliasAttempts {
+				log.Error("failed to generate a free alias", slog.Int("attempts", attempt))
+				resp.RenderError(w, r, resp.NewError(resp.CodeInternal, "failed to add url"))
+				return
+			}
+		}
 		if errors.Is(err, storage.ErrURLExists) {
 			log.Info("url already exists", slog.String("url", req.URL))
-			render.JSON(w, r, resp.Error("url already exists"))
+			resp.RenderError(w, r, resp.NewError(resp.CodeConflict, "url already exists"))
 			return
 		}
 
 		if err != nil {
 			log.Error("failed to add url", sl.Err(err))
-			render.JSON(w, r, resp.Error("failed to add url"))
+			resp.RenderError(w, r, resp.NewError(resp.CodeInternal, "failed to add url"))
 			return
 		}
 
 		log.Info("url added", slog.Int64("id", id))
 
-		responseOK(w, r, alias)
+		if idGenerator, ok := aliasGenerator.(IDAliasGenerator); ok && generated {
+			u.Alias, err = aliasFromID(urlSaver, idGenerator, aliasRules, u.Alias, id)
+			if err != nil {
+				log.Error("failed to replace temporary alias", slog.String("alias", u.Alias), sl.Err(err))
+
+				// Ссылка под временным псевдонимом не должна достаться клиенту
+				if err := urlSaver.DeleteURL(u.Alias); err != nil {
+					log.Error("failed to delete url with temporary alias", slog.String("alias", u.Alias), sl.Err(err))
+				}
+
+				resp.RenderError(w, r, resp.NewError(resp.CodeInternal, "failed to add url"))
+				return
+			}
+		}
+
+		responseOK(w, r, http.StatusCreated, u)
+	}
+}
+
+// aliasFromID заменяет временный псевдоним сохранённой ссылки на построенный из её идентификатора
+// и возвращает итоговый псевдоним. При ошибке возвращается временный псевдоним.
+func aliasFromID(
+	urlSaver URLSaver,
+	idGenerator IDAliasGenerator,
+	aliasRules *alias.Rules,
+	tmp string,
+	id int64,
+) (string, error) {
+	newAlias, err := idGenerator.FromID(id)
+	if err != nil {
+		return tmp, fmt.Errorf("build alias from id: %w", err)
+	}
+
+	if !aliasRules.Allowed(newAlias) {
+		return tmp, fmt.Errorf("alias from id %q is reserved", newAlias)
+	}
+
+	// Псевдоним из идентификатора мог быть заранее занят ссылкой с заданным вручную псевдонимом
+	if err := urlSaver.UpdateURL(tmp, storage.URLUpdate{Alias: &newAlias}); err != nil {
+		return tmp, fmt.Errorf("rename to %q: %w", newAlias, err)
+	}
+
+	return newAlias, nil
+}
+
+// expiration вычисляет момент истечения срока действия ссылки из ExpiresAt или TTL.
+func expiration(req Request, now time.Time) (*time.Time, error) {
+	switch {
+	case req.ExpiresAt != nil:
+		if !req.ExpiresAt.After(now) {
+			return nil, errors.New("field ExpiresAt must be in the future")
+		}
+		t := req.ExpiresAt.UTC()
+		return &t, nil
+	case req.TTL > 0:
+		t := now.Add(time.Duration(req.TTL) * time.Second).UTC()
+		return &t, nil
+	default:
+		return nil, nil
 	}
 }
 
-func responseOK(w http.ResponseWriter, r *http.Request, alias string) {
+func responseOK(w http.ResponseWriter, r *http.Request, status int, u storage.URL) {
+	render.Status(r, status)
 	render.JSON(w, r, Response{
-		Response: resp.OK(),
-		Alias:    alias,
+		Response:    resp.OK(),
+		Alias:       u.Alias,
+		URL:         u.URL,
+		OriginalURL: u.OriginalURL,
+		ExpiresAt:   u.ExpiresAt,
 	})
 }
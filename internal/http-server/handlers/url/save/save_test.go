@@ -3,7 +3,11 @@ package save_test
 import (
 	"URLite/internal/http-server/handlers/url/save"
 	"URLite/internal/http-server/handlers/url/save/mocks"
+	"URLite/internal/http-server/middleware/auth"
+	"URLite/internal/lib/alias"
 	"URLite/internal/lib/logger/handlers/slogdiscard"
+	"URLite/internal/lib/urlnorm"
+	"URLite/internal/storage"
 	"bytes"
 	"encoding/json"
 	"errors"
@@ -13,15 +17,28 @@ import (
 	"math/rand"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
 
+// newAliasRules возвращает правила псевдонимов, общие для тестов обработчика.
+func newAliasRules() *alias.Rules {
+	return alias.NewRules(alias.RulesOptions{
+		MaxLength: 32,
+		Reserved:  []string{"metrics"},
+		Blocklist: []string{"badword"},
+	})
+}
+
 func TestSaveHandler(t *testing.T) {
 	cases := []struct {
 		name      string // Имя теста
 		alias     string // Псевдоним, который передается вместе с URL
 		url       string // URL для сохранения
+		extra     string // Дополнительные поля JSON-запроса
+		expires   bool   // Ожидается ли, что у ссылки будет срок действия
+		code      int    // Ожидаемый код ответа, по умолчанию 201 Created
 		respError string // Ожидаемое сообщение об ошибке в ответе
 		mockError error  // Ошибка, которую должен вернуть мок-объект при попытке сохранения URL
 	}{
@@ -39,18 +56,21 @@ func TestSaveHandler(t *testing.T) {
 			name:      "Empty URL",
 			url:       "",
 			alias:     "some_alias",
+			code:      http.StatusBadRequest,
 			respError: "field URL is a required field",
 		},
 		{
 			name:      "Invalid URL",
 			url:       "some invalid URL",
 			alias:     "some_alias",
+			code:      http.StatusBadRequest,
 			respError: "field URL is not a valid URL",
 		},
 		{
 			name:      "SaveURL Error",
 			alias:     "test_alias",
 			url:       "https://go.dev/",
+			code:      http.StatusInternalServerError,
 			respError: "failed to add url",
 			mockError: errors.New("unexpected error"),
 		},
@@ -64,7 +84,36 @@ func TestSaveHandler(t *testing.T) {
 			name:      "Alias with special characters",
 			alias:     "special_!@#",
 			url:       "https://example.com",
-			respError: "",
+			code:      http.StatusBadRequest,
+			respError: "field Alias may contain only latin letters, digits, '-' and '_'",
+		},
+		{
+			name:      "Alias with slash",
+			alias:     "a/b",
+			url:       "https://example.com",
+			code:      http.StatusBadRequest,
+			respError: "field Alias may contain only latin letters, digits, '-' and '_'",
+		},
+		{
+			name:      "Too long alias",
+			alias:     strings.Repeat("a", 33),
+			url:       "https://example.com",
+			code:      http.StatusBadRequest,
+			respError: "field Alias must be at most 32 characters long",
+		},
+		{
+			name:      "Reserved alias",
+			alias:     "Metrics",
+			url:       "https://example.com",
+			code:      http.StatusBadRequest,
+			respError: "field Alias is reserved",
+		},
+		{
+			name:      "Blocked alias",
+			alias:     "my-BadWord-link",
+			url:       "https://example.com",
+			code:      http.StatusBadRequest,
+			respError: "field Alias contains a blocked word",
 		},
 		{
 			name:      "URL with query parameters",
@@ -72,10 +121,73 @@ func TestSaveHandler(t *testing.T) {
 			url:       "https://example.com/search?q=test",
 			respError: "",
 		},
+		{
+			name:    "With TTL",
+			alias:   "ttl_alias",
+			url:     "https://example.com",
+			extra:   `, "ttl": 3600`,
+			expires: true,
+		},
+		{
+			name:    "With expires_at",
+			alias:   "expiring_alias",
+			url:     "https://example.com",
+			extra:   `, "expires_at": "2999-01-01T00:00:00Z"`,
+			expires: true,
+		},
+		{
+			name:      "Expiration in the past",
+			alias:     "expired_alias",
+			url:       "https://example.com",
+			extra:     `, "expires_at": "2000-01-01T00:00:00Z"`,
+			code:      http.StatusBadRequest,
+			respError: "field ExpiresAt must be in the future",
+		},
+		{
+			name:      "Both TTL and expires_at",
+			alias:     "expiring_alias",
+			url:       "https://example.com",
+			extra:     `, "ttl": 60, "expires_at": "2999-01-01T00:00:00Z"`,
+			code:      http.StatusBadRequest,
+			respError: "field ExpiresAt cannot be used together with TTL",
+		},
+		{
+			name:      "Reuse existing with alias",
+			alias:     "reused_alias",
+			url:       "https://example.com",
+			extra:     `, "reuse_existing": true`,
+			code:      http.StatusBadRequest,
+			respError: "field ReuseExisting cannot be used together with Alias",
+		},
+		{
+			name:      "Negative TTL",
+			alias:     "ttl_alias",
+			url:       "https://example.com",
+			extra:     `, "ttl": -1`,
+			code:      http.StatusBadRequest,
+			respError: "field TTL is not valid",
+		},
+		{
+			name:      "TTL overflows duration",
+			alias:     "ttl_alias",
+			url:       "https://example.com",
+			extra:     `, "ttl": 9223372036854775807`,
+			code:      http.StatusBadRequest,
+			respError: "field TTL must be at most 3153600000",
+		},
+		{
+			name:      "Alias exists",
+			alias:     "taken_alias",
+			url:       "https://example.com/",
+			code:      http.StatusConflict,
+			respError: "url already exists",
+			mockError: storage.ErrURLExists,
+		},
 		{
 			name:      "Duplicate URL Error",
 			alias:     "duplicate_alias",
 			url:       "https://example.com/",
+			code:      http.StatusInternalServerError,
 			respError: "failed to add url",
 			mockError: errors.New("duplicate URL error"),
 		},
@@ -97,29 +209,44 @@ func TestSaveHandler(t *testing.T) {
 
 			// Настраиваем мок-объект в зависимости от тестового случая
 			if tc.respError == "" || tc.mockError != nil {
-				urlSaverMock.On("SaveURL", tc.url, mock.AnythingOfType("string")).
+				urlSaverMock.On("SaveURL", mock.MatchedBy(func(u storage.URL) bool {
+					return u.URL == tc.url && u.Alias != "" && (u.ExpiresAt != nil) == tc.expires && u.Owner == "team-a"
+				})).
 					Return(randomID, tc.mockError).
 					Once()
 			}
 
+			// Псевдоним генерируется, только если клиент его не указал
+			aliasGeneratorMock := mocks.NewAliasGenerator(t)
+			if tc.alias == "" && (tc.respError == "" || tc.mockError != nil) {
+				aliasGeneratorMock.On("Generate").Return("generated", nil).Once()
+			}
+
 			// Создаем обработчик с использованием мок-объекта
-			handler := save.New(slogdiscard.NewDiscardLogger(), urlSaverMock)
+			handler := save.New(slogdiscard.NewDiscardLogger(), urlSaverMock, aliasGeneratorMock, newAliasRules(), urlnorm.New(urlnorm.Options{}))
 
 			// Формируем входные данные для запроса
-			input := fmt.Sprintf(`{"url": "%s", "alias": "%s"}`, tc.url, tc.alias)
+			input := fmt.Sprintf(`{"url": "%s", "alias": "%s"%s}`, tc.url, tc.alias, tc.extra)
 
 			// Создаем новый HTTP-запрос с использованием сформированных данных
 			req, err := http.NewRequest(http.MethodPost, "/save", bytes.NewReader([]byte(input)))
 			require.NoError(t, err)
 
+			// Ссылку создаёт клиент, владеющий ключом team-a
+			req = req.WithContext(auth.WithPrincipal(req.Context(), auth.Principal{Owner: "team-a"}))
+
 			// Создаем новый HTTP-респондер для записи ответа
 			rr := httptest.NewRecorder()
 
 			// Вызываем обработчик с созданным запросом
 			handler.ServeHTTP(rr, req)
 
-			// Проверяем, что код ответа соответствует ожидаемому (например, 200 OK)
-			require.Equal(t, http.StatusOK, rr.Code)
+			// Проверяем, что код ответа соответствует ожидаемому
+			code := tc.code
+			if code == 0 {
+				code = http.StatusCreated
+			}
+			require.Equal(t, code, rr.Code)
 
 			// Извлекаем тело ответа
 			body := rr.Body.String()
@@ -130,6 +257,8 @@ func TestSaveHandler(t *testing.T) {
 
 			// Проверяем, что в ответе содержится ожидаемое сообщение об ошибке
 			require.Equal(t, tc.respError, resp.Error)
+			// Ошибка сопровождается машиночитаемым кодом
+			require.Equal(t, tc.respError != "", resp.Code != "")
 
 			// Проверяем, что поле Alias в ответе заполнено, если ожидается успешный результат
 			if tc.respError == "" {
@@ -137,6 +266,353 @@ func TestSaveHandler(t *testing.T) {
 				if tc.alias != "" {
 					require.Equal(t, tc.alias, resp.Alias, "Expected the alias in response to match the input alias")
 				}
+				require.Equal(t, tc.expires, resp.ExpiresAt != nil)
+			}
+		})
+	}
+}
+
+func TestSaveHandler_GeneratedAliasCollision(t *testing.T) {
+	cases := []struct {
+		name       string
+		taken      int // Сколько сгенерированных псевдонимов подряд оказываются заняты
+		code       int
+		respError  string
+		savedAlias string
+	}{
+		{
+			name:       "Retry after collision",
+			taken:      2,
+			code:       http.StatusCreated,
+			savedAlias: "alias3",
+		},
+		{
+			name:      "All attempts collide",
+			taken:     5,
+			code:      http.StatusInternalServerError,
+			respError: "failed to add url",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			urlSaverMock := mocks.NewURLSaver(t)
+			aliasGeneratorMock := mocks.NewAliasGenerator(t)
+
+			attempts := tc.taken
+			if tc.respError == "" {
+				attempts++
+			}
+
+			for i := 1; i <= attempts; i++ {
+				generated := fmt.Sprintf("alias%d", i)
+				aliasGeneratorMock.On("Generate").Return(generated, nil).Once()
+
+				if i <= tc.taken {
+					urlSaverMock.On("SaveURL", mock.MatchedBy(func(u storage.URL) bool { return u.Alias == generated })).
+						Return(int64(0), storage.ErrURLExists).Once()
+				} else {
+					urlSaverMock.On("SaveURL", mock.MatchedBy(func(u storage.URL) bool { return u.Alias == generated })).
+						Return(int64(1), nil).Once()
+				}
+			}
+			aliasGeneratorMock.On("ReportCollision").Return().Times(tc.taken)
+
+			handler := save.New(slogdiscard.NewDiscardLogger(), urlSaverMock, aliasGeneratorMock, newAliasRules(), urlnorm.New(urlnorm.Options{}))
+
+			req := httptest.NewRequest(http.MethodPost, "/url", bytes.NewReader([]byte(`{"url": "https://go.dev/"}`)))
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.code, rr.Code)
+
+			var resp save.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tc.respError, resp.Error)
+			require.Equal(t, tc.savedAlias, resp.Alias)
+		})
+	}
+}
+
+func TestSaveHandler_ReservedGeneratedAlias(t *testing.T) {
+	urlSaverMock := mocks.NewURLSaver(t)
+	aliasGeneratorMock := mocks.NewAliasGenerator(t)
+
+	aliasGeneratorMock.On("Generate").Return("metrics", nil).Once()
+	aliasGeneratorMock.On("ReportCollision").Return().Once()
+	aliasGeneratorMock.On("Generate").Return("free", nil).Once()
+	urlSaverMock.On("SaveURL", mock.MatchedBy(func(u storage.URL) bool { return u.Alias == "free" })).
+		Return(int64(1), nil).Once()
+
+	handler := save.New(slogdiscard.NewDiscardLogger(), urlSaverMock, aliasGeneratorMock, newAliasRules(), urlnorm.New(urlnorm.Options{}))
+
+	req := httptest.NewRequest(http.MethodPost, "/url", bytes.NewReader([]byte(`{"url": "https://go.dev/"}`)))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusCreated, rr.Code)
+
+	var resp save.Response
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Equal(t, "free", resp.Alias)
+}
+
+func TestSaveHandler_LowercaseAlias(t *testing.T) {
+	rules := alias.NewRules(alias.RulesOptions{MinLength: 3, Lowercase: true})
+	handler := save.New(slogdiscard.NewDiscardLogger(), mocks.NewURLSaver(t), mocks.NewAliasGenerator(t), rules, urlnorm.New(urlnorm.Options{}))
+
+	req := httptest.NewRequest(http.MethodPost, "/url", bytes.NewReader([]byte(`{"url": "https://go.dev/", "alias": "GoDev"}`)))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var resp save.Response
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+	require.Equal(t, "field Alias must be lowercase", resp.Error)
+	require.Len(t, resp.Details, 1)
+	require.Equal(t, "alias", resp.Details[0].Field)
+	require.Equal(t, "alias_case", resp.Details[0].Rule)
+}
+
+func TestSaveHandler_AliasFromID(t *testing.T) {
+	cases := []struct {
+		name        string
+		body        string
+		idAlias     string
+		fromIDError error
+		renameError error
+		deleteError error
+		respCode    int
+		respAlias   string
+		respError   string
+	}{
+		{
+			name:      "Alias from id",
+			body:      `{"url": "https://go.dev/"}`,
+			idAlias:   "Gx",
+			respCode:  http.StatusCreated,
+			respAlias: "Gx",
+		},
+		{
+			name:        "Alias from id is taken",
+			body:        `{"url": "https://go.dev/"}`,
+			idAlias:     "Gx",
+			renameError: storage.ErrURLExists,
+			respCode:    http.StatusInternalServerError,
+			respError:   "failed to add url",
+		},
+		{
+			name:        "Rename Error",
+			body:        `{"url": "https://go.dev/"}`,
+			idAlias:     "Gx",
+			renameError: errors.New("unexpected error"),
+			respCode:    http.StatusInternalServerError,
+			respError:   "failed to add url",
+		},
+		{
+			name:        "Rename and Delete Error",
+			body:        `{"url": "https://go.dev/"}`,
+			idAlias:     "Gx",
+			renameError: errors.New("unexpected error"),
+			deleteError: errors.New("unexpected error"),
+			respCode:    http.StatusInternalServerError,
+			respError:   "failed to add url",
+		},
+		{
+			name:      "Alias from id is reserved",
+			body:      `{"url": "https://go.dev/"}`,
+			idAlias:   "metrics",
+			respCode:  http.StatusInternalServerError,
+			respError: "failed to add url",
+		},
+		{
+			name:        "FromID Error",
+			body:        `{"url": "https://go.dev/"}`,
+			fromIDError: errors.New("unexpected error"),
+			respCode:    http.StatusInternalServerError,
+			respError:   "failed to add url",
+		},
+		{
+			name:      "Custom alias is kept",
+			body:      `{"url": "https://go.dev/", "alias": "go"}`,
+			respCode:  http.StatusCreated,
+			respAlias: "go",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			urlSaverMock := mocks.NewURLSaver(t)
+			aliasGeneratorMock := mocks.NewIDAliasGenerator(t)
+
+			if tc.respAlias == "go" {
+				urlSaverMock.On("SaveURL", mock.MatchedBy(func(u storage.URL) bool { return u.Alias == "go" })).
+					Return(int64(42), nil).Once()
+			} else {
+				aliasGeneratorMock.On("Generate").Return("tmp_alias", nil).Once()
+				urlSaverMock.On("SaveURL", mock.MatchedBy(func(u storage.URL) bool { return u.Alias == "tmp_alias" })).
+					Return(int64(42), nil).Once()
+				aliasGeneratorMock.On("FromID", int64(42)).Return(tc.idAlias, tc.fromIDError).Once()
+
+				if tc.fromIDError == nil && tc.idAlias == "Gx" {
+					urlSaverMock.On("UpdateURL", "tmp_alias", mock.MatchedBy(func(upd storage.URLUpdate) bool {
+						return upd.Alias != nil && *upd.Alias == "Gx" && upd.URL == nil && upd.ExpiresAt == nil
+					})).Return(tc.renameError).Once()
+				}
+
+				if tc.respError != "" {
+					// Ссылка под временным псевдонимом удаляется
+					urlSaverMock.On("DeleteURL", "tmp_alias").Return(tc.deleteError).Once()
+				}
+			}
+
+			handler := save.New(slogdiscard.NewDiscardLogger(), urlSaverMock, aliasGeneratorMock, newAliasRules(), urlnorm.New(urlnorm.Options{}))
+
+			req := httptest.NewRequest(http.MethodPost, "/url", bytes.NewReader([]byte(tc.body)))
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.respCode, rr.Code)
+
+			var resp save.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tc.respError, resp.Error)
+			require.Equal(t, tc.respAlias, resp.Alias)
+		})
+	}
+}
+
+func TestSaveHandler_NormalizesURL(t *testing.T) {
+	const (
+		original  = "HTTP://Example.COM:80/a/../b?utm_source=mail&id=7"
+		canonical = "http://example.com/b?id=7"
+	)
+
+	urlSaverMock := mocks.NewURLSaver(t)
+	urlSaverMock.On("SaveURL", mock.MatchedBy(func(u storage.URL) bool {
+		return u.URL == canonical && u.OriginalURL == original && u.Alias == "example"
+	})).Return(int64(1), nil).Once()
+
+	normalizer := urlnorm.New(urlnorm.Options{StripTracking: true})
+	handler := save.New(slogdiscard.NewDiscardLogger(), urlSaverMock, mocks.NewAliasGenerator(t), newAliasRules(), normalizer)
+
+	body := `{"url": "` + original + `", "alias": "example"}`
+	req := httptest.NewRequest(http.MethodPost, "/url", bytes.NewReader([]byte(body)))
+	req = req.WithContext(auth.WithPrincipal(req.Context(), auth.Principal{Owner: "team-a"}))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusCreated, rr.Code)
+
+	var resp save.Response
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Equal(t, canonical, resp.URL)
+	require.Equal(t, original, resp.OriginalURL)
+
+	// Тот же адрес в другой записи находит созданную ссылку
+	urlSaverMock.On("FindURL", "team-a", canonical).
+		Return(storage.URL{Alias: "example", URL: canonical, OriginalURL: original}, nil).Once()
+
+	body = `{"url": "http://example.com/b?id=7&fbclid=abc", "reuse_existing": true}`
+	req = httptest.NewRequest(http.MethodPost, "/url", bytes.NewReader([]byte(body)))
+	req = req.WithContext(auth.WithPrincipal(req.Context(), auth.Principal{Owner: "team-a"}))
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	// Для найденной ссылки возвращается адрес, переданный при её создании
+	var reused save.Response
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &reused))
+	require.Equal(t, "example", reused.Alias)
+	require.Equal(t, canonical, reused.URL)
+	require.Equal(t, original, reused.OriginalURL)
+}
+
+func TestSaveHandler_ReuseExisting(t *testing.T) {
+	expiresAt := time.Date(2999, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name      string
+		existing  *storage.URL // Ссылка, которую находит хранилище
+		findError error
+		code      int
+		respError string
+		respAlias string
+	}{
+		{
+			name:      "Existing url is reused",
+			existing:  &storage.URL{ID: 7, Alias: "existing", URL: "https://go.dev/", Owner: "team-a", ExpiresAt: &expiresAt},
+			code:      http.StatusOK,
+			respAlias: "existing",
+		},
+		{
+			name:      "No existing url",
+			findError: storage.ErrURLNotFound,
+			code:      http.StatusCreated,
+			respAlias: "generated",
+		},
+		{
+			name:      "FindURL Error",
+			findError: errors.New("unexpected error"),
+			code:      http.StatusInternalServerError,
+			respError: "failed to add url",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			urlSaverMock := mocks.NewURLSaver(t)
+			aliasGeneratorMock := mocks.NewAliasGenerator(t)
+
+			var existing storage.URL
+			if tc.existing != nil {
+				existing = *tc.existing
+			}
+			// Ищутся только ссылки самого клиента
+			urlSaverMock.On("FindURL", "team-a", "https://go.dev/").Return(existing, tc.findError).Once()
+
+			if errors.Is(tc.findError, storage.ErrURLNotFound) {
+				aliasGeneratorMock.On("Generate").Return("generated", nil).Once()
+				urlSaverMock.On("SaveURL", mock.MatchedBy(func(u storage.URL) bool { return u.Alias == "generated" })).
+					Return(int64(8), nil).Once()
+			}
+
+			handler := save.New(slogdiscard.NewDiscardLogger(), urlSaverMock, aliasGeneratorMock, newAliasRules(), urlnorm.New(urlnorm.Options{}))
+
+			body := `{"url": "https://go.dev/", "reuse_existing": true}`
+			req := httptest.NewRequest(http.MethodPost, "/url", bytes.NewReader([]byte(body)))
+			req = req.WithContext(auth.WithPrincipal(req.Context(), auth.Principal{Owner: "team-a"}))
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.code, rr.Code)
+
+			var resp save.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tc.respError, resp.Error)
+			require.Equal(t, tc.respAlias, resp.Alias)
+
+			if tc.existing != nil {
+				require.NotNil(t, resp.ExpiresAt)
+				require.True(t, expiresAt.Equal(*resp.ExpiresAt))
 			}
 		})
 	}
@@ -0,0 +1,156 @@
+package update
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+
+	"URLite/internal/http-server/middleware/auth"
+	resp "URLite/internal/lib/api/response"
+	"URLite/internal/lib/logger/sl"
+	"URLite/internal/lib/urlnorm"
+	"URLite/internal/lib/validate"
+	"URLite/internal/storage"
+)
+
+// Request — тело PATCH-запроса. Пустые поля не изменяются.
+type Request struct {
+	URL string `json:"url,omitempty" validate:"omitempty,url"`
+	// ExpiresAt и TTL (в секундах, не больше 100 лет) задают новый срок действия ссылки.
+	ExpiresAt *time.Time `json:"expires_at,omitempty" validate:"omitempty,excluded_with=TTL"`
+	TTL       int64      `json:"ttl,omitempty" validate:"omitempty,gt=0,max=3153600000"`
+	// ClearExpiration снимает срок действия, делая ссылку бессрочной. Нельзя указывать вместе с ExpiresAt и TTL.
+	ClearExpiration bool `json:"clear_expiration,omitempty" validate:"excluded_with=ExpiresAt TTL"`
+}
+
+type Response struct {
+	resp.Response
+	Alias string `json:"alias,omitempty"`
+}
+
+// URLUpdater — интерфейс для изменения ссылки по псевдониму.
+// GetURLInfo нужен, чтобы проверить владельца ссылки перед изменением.
+//
+//go:generate go run github.com/vektra/mockery/v2@v2.28.2 --name=URLUpdater
+type URLUpdater interface {
+	GetURLInfo(alias string) (storage.URL, error)
+	UpdateURL(alias string, upd storage.URLUpdate) error
+}
+
+// New возвращает функцию-обработчик HTTP-запросов для изменения ссылки по псевдониму.
+// Изменить ссылку может только её владелец или администратор.
+// Новый адрес сохраняется в виде, приведённом normalizer, а исходный адрес — рядом с ним.
+func New(log *slog.Logger, urlUpdater URLUpdater, normalizer *urlnorm.Normalizer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.update.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		alias := chi.URLParam(r, "alias")
+		if alias == "" {
+			log.Info("empty alias")
+			resp.RenderError(w, r, resp.NewError(resp.CodeBadRequest, "incorrect request"))
+			return
+		}
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			resp.RenderError(w, r, resp.NewError(resp.CodeBadRequest, "failed to decode request"))
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if err := validate.Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+			log.Error("invalid request", sl.Err(err))
+			resp.RenderError(w, r, resp.NewValidationError(validateErr))
+			return
+		}
+
+		var upd storage.URLUpdate
+		if req.URL != "" {
+			target, err := normalizer.Normalize(req.URL)
+			if err != nil {
+				log.Info("failed to normalize url", sl.Err(err))
+				resp.RenderError(w, r, resp.NewError(resp.CodeBadRequest, "field URL is not a valid URL"))
+				return
+			}
+
+			upd.URL = &target
+			upd.OriginalURL = &req.URL
+		}
+
+		now := time.Now()
+		switch {
+		case req.ExpiresAt != nil:
+			if !req.ExpiresAt.After(now) {
+				log.Info("expiration in the past")
+				resp.RenderError(w, r, resp.NewError(resp.CodeBadRequest, "field ExpiresAt must be in the future"))
+				return
+			}
+			t := req.ExpiresAt.UTC()
+			upd.ExpiresAt = &t
+		case req.TTL > 0:
+			t := now.Add(time.Duration(req.TTL) * time.Second).UTC()
+			upd.ExpiresAt = &t
+		case req.ClearExpiration:
+			upd.ClearExpiresAt = true
+		}
+
+		if upd == (storage.URLUpdate{}) {
+			log.Info("nothing to update")
+			resp.RenderError(w, r, resp.NewError(resp.CodeBadRequest, "nothing to update"))
+			return
+		}
+
+		u, err := urlUpdater.GetURLInfo(alias)
+		if errors.Is(err, storage.ErrURLNotFound) {
+			log.Info("url not found", slog.String("alias", alias))
+			resp.RenderError(w, r, resp.NewError(resp.CodeNotFound, "not found"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to get url", sl.Err(err))
+			resp.RenderError(w, r, resp.NewError(resp.CodeInternal, "internal error"))
+			return
+		}
+
+		principal, _ := auth.PrincipalFromContext(r.Context())
+		if !principal.CanManage(u.Owner) {
+			log.Info("url is owned by another client", slog.String("alias", alias), slog.String("owner", u.Owner))
+			resp.RenderError(w, r, resp.NewError(resp.CodeForbidden, "forbidden"))
+			return
+		}
+
+		err = urlUpdater.UpdateURL(alias, upd)
+		if errors.Is(err, storage.ErrURLNotFound) {
+			log.Info("url not found", slog.String("alias", alias))
+			resp.RenderError(w, r, resp.NewError(resp.CodeNotFound, "not found"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to update url", sl.Err(err))
+			resp.RenderError(w, r, resp.NewError(resp.CodeInternal, "internal error"))
+			return
+		}
+
+		log.Info("url updated", slog.String("alias", alias))
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Alias:    alias,
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package update_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"URLite/internal/http-server/handlers/url/update"
+	"URLite/internal/http-server/handlers/url/update/mocks"
+	"URLite/internal/http-server/middleware/auth"
+	"URLite/internal/lib/logger/handlers/slogdiscard"
+	"URLite/internal/lib/urlnorm"
+	"URLite/internal/storage"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateHandler(t *testing.T) {
+	cases := []struct {
+		name      string
+		alias     string
+		body      string
+		url       string // URL, который должен быть передан в хранилище
+		expires   bool   // Должен ли быть передан новый срок действия
+		clear     bool   // Должен ли быть снят срок действия
+		owner     string // Владелец ссылки, по умолчанию совпадает с клиентом
+		code      int
+		respError string
+		mockError error
+	}{
+		{
+			name:  "Success",
+			alias: "test_alias",
+			body:  `{"url": "https://go.dev/"}`,
+			url:   "https://go.dev/",
+			code:  http.StatusOK,
+		},
+		{
+			name:  "Normalized URL",
+			alias: "test_alias",
+			body:  `{"url": "HTTPS://Go.dev:443/doc/./install"}`,
+			url:   "https://go.dev/doc/install",
+			code:  http.StatusOK,
+		},
+		{
+			name:    "Extend expiration",
+			alias:   "test_alias",
+			body:    `{"ttl": 3600}`,
+			expires: true,
+			code:    http.StatusOK,
+		},
+		{
+			name:  "Clear expiration",
+			alias: "test_alias",
+			body:  `{"clear_expiration": true}`,
+			clear: true,
+			code:  http.StatusOK,
+		},
+		{
+			name:      "Clear expiration with TTL",
+			alias:     "test_alias",
+			body:      `{"clear_expiration": true, "ttl": 3600}`,
+			code:      http.StatusBadRequest,
+			respError: "field ClearExpiration cannot be used together with ExpiresAt or TTL",
+		},
+		{
+			name:      "Expiration in the past",
+			alias:     "test_alias",
+			body:      `{"expires_at": "2000-01-01T00:00:00Z"}`,
+			code:      http.StatusBadRequest,
+			respError: "field ExpiresAt must be in the future",
+		},
+		{
+			name:      "TTL overflows duration",
+			alias:     "test_alias",
+			body:      `{"ttl": 9223372036854775807}`,
+			code:      http.StatusBadRequest,
+			respError: "field TTL must be at most 3153600000",
+		},
+		{
+			name:      "Invalid URL",
+			alias:     "test_alias",
+			body:      `{"url": "some invalid URL"}`,
+			code:      http.StatusBadRequest,
+			respError: "field URL is not a valid URL",
+		},
+		{
+			name:      "Nothing to update",
+			alias:     "test_alias",
+			body:      `{}`,
+			code:      http.StatusBadRequest,
+			respError: "nothing to update",
+		},
+		{
+			name:      "Broken body",
+			alias:     "test_alias",
+			body:      `{"url":`,
+			code:      http.StatusBadRequest,
+			respError: "failed to decode request",
+		},
+		{
+			name:      "URL Not Found",
+			alias:     "nonexistent_alias",
+			body:      `{"url": "https://go.dev/"}`,
+			url:       "https://go.dev/",
+			code:      http.StatusNotFound,
+			respError: "not found",
+			mockError: storage.ErrURLNotFound,
+		},
+		{
+			name:      "Foreign URL",
+			alias:     "test_alias",
+			body:      `{"url": "https://go.dev/"}`,
+			owner:     "team-b",
+			code:      http.StatusForbidden,
+			respError: "forbidden",
+		},
+		{
+			name:      "UpdateURL Error",
+			alias:     "error_alias",
+			body:      `{"url": "https://go.dev/"}`,
+			url:       "https://go.dev/",
+			code:      http.StatusInternalServerError,
+			respError: "internal error",
+			mockError: errors.New("unexpected error"),
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			urlUpdaterMock := mocks.NewURLUpdater(t)
+
+			owner := tc.owner
+			if owner == "" {
+				owner = "team-a"
+			}
+
+			if tc.code == http.StatusForbidden || errors.Is(tc.mockError, storage.ErrURLNotFound) {
+				urlUpdaterMock.On("GetURLInfo", tc.alias).Return(storage.URL{Alias: tc.alias, Owner: owner}, tc.mockError).Once()
+			} else if tc.url != "" || tc.expires || tc.clear {
+				urlUpdaterMock.On("GetURLInfo", tc.alias).Return(storage.URL{Alias: tc.alias, Owner: owner}, nil).Once()
+				urlUpdaterMock.On("UpdateURL", tc.alias, mock.MatchedBy(func(upd storage.URLUpdate) bool {
+					if tc.url != "" && (upd.URL == nil || *upd.URL != tc.url || upd.OriginalURL == nil) {
+						return false
+					}
+					return (upd.ExpiresAt != nil) == tc.expires && upd.ClearExpiresAt == tc.clear
+				})).
+					Return(tc.mockError).
+					Once()
+			}
+
+			r := chi.NewRouter()
+			r.Use(func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					principal := auth.Principal{Owner: "team-a", Role: storage.RoleEditor}
+					next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+				})
+			})
+			r.Patch("/{alias}", update.New(slogdiscard.NewDiscardLogger(), urlUpdaterMock, urlnorm.New(urlnorm.Options{})))
+
+			req, err := http.NewRequest(http.MethodPatch, "/"+tc.alias, bytes.NewReader([]byte(tc.body)))
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.code, rr.Code)
+
+			var resp update.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tc.respError, resp.Error)
+
+			if tc.respError == "" {
+				require.Equal(t, tc.alias, resp.Alias)
+			}
+		})
+	}
+}
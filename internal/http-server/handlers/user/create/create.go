@@ -0,0 +1,103 @@
+package create
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+
+	resp "URLite/internal/lib/api/response"
+	"URLite/internal/lib/logger/sl"
+	"URLite/internal/lib/password"
+	"URLite/internal/lib/validate"
+	"URLite/internal/storage"
+)
+
+type Request struct {
+	// Username не может содержать ':': такое имя нельзя передать в Basic-аутентификации.
+	Username string `json:"username" validate:"required,excludes=:"`
+	Password string `json:"password" validate:"required,min=8"`
+	Role     string `json:"role" validate:"required,oneof=admin editor viewer"`
+}
+
+type Response struct {
+	resp.Response
+	User *storage.User `json:"user,omitempty"`
+}
+
+// UserSaver — интерфейс для сохранения пользователя.
+//
+//go:generate go run github.com/vektra/mockery/v2@v2.28.2 --name=UserSaver
+type UserSaver interface {
+	SaveUser(u storage.User) (int64, error)
+}
+
+// New возвращает функцию-обработчик HTTP-запросов для создания пользователя.
+func New(log *slog.Logger, userSaver UserSaver) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.user.create.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			resp.RenderError(w, r, resp.NewError(resp.CodeBadRequest, "failed to decode request"))
+			return
+		}
+
+		// Пароль не попадает в логи
+		log.Info("request body decoded", slog.String("username", req.Username), slog.String("role", req.Role))
+
+		if err := validate.Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+			log.Error("invalid request", sl.Err(err))
+			resp.RenderError(w, r, resp.NewValidationError(validateErr))
+			return
+		}
+
+		hash, err := password.Hash(req.Password)
+		if err != nil {
+			log.Error("failed to hash password", sl.Err(err))
+			resp.RenderError(w, r, resp.NewError(resp.CodeInternal, "internal error"))
+			return
+		}
+
+		u := storage.User{
+			Username:     req.Username,
+			PasswordHash: hash,
+			Role:         req.Role,
+			CreatedAt:    time.Now().UTC(),
+		}
+
+		id, err := userSaver.SaveUser(u)
+		if errors.Is(err, storage.ErrUserExists) {
+			log.Info("user already exists", slog.String("username", req.Username))
+			resp.RenderError(w, r, resp.NewError(resp.CodeConflict, "user already exists"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to save user", sl.Err(err))
+			resp.RenderError(w, r, resp.NewError(resp.CodeInternal, "internal error"))
+			return
+		}
+
+		u.ID = id
+
+		log.Info("user created", slog.Int64("id", id))
+
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			User:     &u,
+		})
+	}
+}
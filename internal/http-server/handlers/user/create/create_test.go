@@ -0,0 +1,128 @@
+package create_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"URLite/internal/http-server/handlers/user/create"
+	"URLite/internal/http-server/handlers/user/create/mocks"
+	"URLite/internal/lib/logger/handlers/slogdiscard"
+	"URLite/internal/lib/password"
+	"URLite/internal/storage"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateHandler(t *testing.T) {
+	cases := []struct {
+		name      string
+		body      string
+		username  string // Имя, которое должно быть сохранено
+		role      string // Роль, которая должна быть сохранена
+		code      int
+		respError string
+		mockError error
+	}{
+		{
+			name:     "Success",
+			body:     `{"username": "alice", "password": "secret-pass", "role": "editor"}`,
+			username: "alice",
+			role:     "editor",
+			code:     http.StatusCreated,
+		},
+		{
+			name:      "Short password",
+			body:      `{"username": "alice", "password": "short", "role": "editor"}`,
+			code:      http.StatusBadRequest,
+			respError: "field Password must be at least 8 characters long",
+		},
+		{
+			name:      "Unknown role",
+			body:      `{"username": "alice", "password": "secret-pass", "role": "root"}`,
+			code:      http.StatusBadRequest,
+			respError: "field Role must be one of: admin editor viewer",
+		},
+		{
+			name:      "Empty username",
+			body:      `{"password": "secret-pass", "role": "viewer"}`,
+			code:      http.StatusBadRequest,
+			respError: "field Username is a required field",
+		},
+		{
+			name:      "Username with colon",
+			body:      `{"username": "key:alice", "password": "secret-pass", "role": "viewer"}`,
+			code:      http.StatusBadRequest,
+			respError: `field Username must not contain ":"`,
+		},
+		{
+			name:      "Broken body",
+			body:      `{"username":`,
+			code:      http.StatusBadRequest,
+			respError: "failed to decode request",
+		},
+		{
+			name:      "User exists",
+			body:      `{"username": "alice", "password": "secret-pass", "role": "viewer"}`,
+			username:  "alice",
+			role:      "viewer",
+			code:      http.StatusConflict,
+			respError: "user already exists",
+			mockError: storage.ErrUserExists,
+		},
+		{
+			name:      "SaveUser Error",
+			body:      `{"username": "alice", "password": "secret-pass", "role": "admin"}`,
+			username:  "alice",
+			role:      "admin",
+			code:      http.StatusInternalServerError,
+			respError: "internal error",
+			mockError: errors.New("unexpected error"),
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			userSaverMock := mocks.NewUserSaver(t)
+
+			if tc.username != "" {
+				userSaverMock.On("SaveUser", mock.MatchedBy(func(u storage.User) bool {
+					return u.Username == tc.username && u.Role == tc.role &&
+						password.Compare(u.PasswordHash, "secret-pass") && !u.CreatedAt.IsZero()
+				})).Return(int64(1), tc.mockError).Once()
+			}
+
+			handler := create.New(slogdiscard.NewDiscardLogger(), userSaverMock)
+
+			req, err := http.NewRequest(http.MethodPost, "/admin/users", bytes.NewReader([]byte(tc.body)))
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.code, rr.Code)
+			// Ни пароль, ни его хеш не должны попадать в ответ
+			require.NotContains(t, rr.Body.String(), "secret-pass")
+			require.NotContains(t, rr.Body.String(), "$2a$")
+
+			var resp create.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tc.respError, resp.Error)
+
+			if tc.respError == "" {
+				require.NotNil(t, resp.User)
+				require.Equal(t, int64(1), resp.User.ID)
+				require.Equal(t, tc.username, resp.User.Username)
+				require.Equal(t, tc.role, resp.User.Role)
+			}
+		})
+	}
+}
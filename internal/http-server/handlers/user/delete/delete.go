@@ -0,0 +1,66 @@
+package delete
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+
+	"URLite/internal/http-server/middleware/auth"
+	resp "URLite/internal/lib/api/response"
+	"URLite/internal/lib/logger/sl"
+	"URLite/internal/storage"
+)
+
+// UserDeleter — интерфейс для удаления пользователя по имени.
+//
+//go:generate go run github.com/vektra/mockery/v2@v2.28.2 --name=UserDeleter
+type UserDeleter interface {
+	DeleteUser(username string) error
+}
+
+// New возвращает функцию-обработчик HTTP-запросов для удаления пользователя.
+// Удалить собственную учётную запись нельзя, чтобы не остаться без администратора.
+// Ссылки пользователя остаются без владельца, и новый пользователь с тем же именем их не получает.
+func New(log *slog.Logger, userDeleter UserDeleter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.user.delete.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		username := chi.URLParam(r, "username")
+		if username == "" {
+			log.Info("empty username")
+			resp.RenderError(w, r, resp.NewError(resp.CodeBadRequest, "incorrect request"))
+			return
+		}
+
+		if principal, _ := auth.PrincipalFromContext(r.Context()); principal.APIKeyID == 0 && principal.Owner == username {
+			log.Info("attempt to delete own account", slog.String("username", username))
+			resp.RenderError(w, r, resp.NewError(resp.CodeBadRequest, "cannot delete own account"))
+			return
+		}
+
+		err := userDeleter.DeleteUser(username)
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("user not found", slog.String("username", username))
+			resp.RenderError(w, r, resp.NewError(resp.CodeNotFound, "not found"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to delete user", sl.Err(err))
+			resp.RenderError(w, r, resp.NewError(resp.CodeInternal, "internal error"))
+			return
+		}
+
+		log.Info("user deleted", slog.String("username", username))
+
+		render.JSON(w, r, resp.OK())
+	}
+}
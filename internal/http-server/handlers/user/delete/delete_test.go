@@ -0,0 +1,96 @@
+package delete_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"URLite/internal/http-server/handlers/user/delete"
+	"URLite/internal/http-server/handlers/user/delete/mocks"
+	"URLite/internal/http-server/middleware/auth"
+	"URLite/internal/lib/api/response"
+	"URLite/internal/lib/logger/handlers/slogdiscard"
+	"URLite/internal/storage"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteHandler(t *testing.T) {
+	cases := []struct {
+		name      string
+		username  string
+		principal auth.Principal
+		delete    bool // Должно ли быть вызвано удаление
+		code      int
+		respError string
+		mockError error
+	}{
+		{
+			name:      "Success",
+			username:  "alice",
+			principal: auth.Principal{Owner: "admin", Role: storage.RoleAdmin},
+			delete:    true,
+			code:      http.StatusOK,
+		},
+		{
+			name:      "Own account",
+			username:  "admin",
+			principal: auth.Principal{Owner: "admin", Role: storage.RoleAdmin},
+			code:      http.StatusBadRequest,
+			respError: "cannot delete own account",
+		},
+		{
+			name:      "User Not Found",
+			username:  "bob",
+			principal: auth.Principal{Owner: "admin", Role: storage.RoleAdmin},
+			delete:    true,
+			code:      http.StatusNotFound,
+			respError: "not found",
+			mockError: storage.ErrUserNotFound,
+		},
+		{
+			name:      "DeleteUser Error",
+			username:  "alice",
+			principal: auth.Principal{Owner: "admin", Role: storage.RoleAdmin},
+			delete:    true,
+			code:      http.StatusInternalServerError,
+			respError: "internal error",
+			mockError: errors.New("unexpected error"),
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			userDeleterMock := mocks.NewUserDeleter(t)
+
+			if tc.delete {
+				userDeleterMock.On("DeleteUser", tc.username).Return(tc.mockError).Once()
+			}
+
+			r := chi.NewRouter()
+			r.Use(func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), tc.principal)))
+				})
+			})
+			r.Delete("/admin/users/{username}", delete.New(slogdiscard.NewDiscardLogger(), userDeleterMock))
+
+			req := httptest.NewRequest(http.MethodDelete, "/admin/users/"+tc.username, nil)
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.code, rr.Code)
+
+			var resp response.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tc.respError, resp.Error)
+		})
+	}
+}
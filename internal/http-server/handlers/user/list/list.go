@@ -0,0 +1,69 @@
+package list
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+
+	resp "URLite/internal/lib/api/response"
+	"URLite/internal/lib/logger/sl"
+	"URLite/internal/storage"
+)
+
+type Response struct {
+	resp.Response
+	Users []storage.User `json:"users"`
+}
+
+// UserLister — интерфейс для получения всех пользователей.
+//
+//go:generate go run github.com/vektra/mockery/v2@v2.28.2 --name=UserLister
+type UserLister interface {
+	ListUsers() ([]storage.User, error)
+}
+
+// New возвращает функцию-обработчик HTTP-запросов для получения списка пользователей.
+// Параметр запроса role оставляет в списке только пользователей с этой ролью.
+func New(log *slog.Logger, userLister UserLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.user.list.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		role := r.URL.Query().Get("role")
+		switch role {
+		case "", storage.RoleViewer, storage.RoleEditor, storage.RoleAdmin:
+		default:
+			log.Info("invalid role filter", slog.String("role", role))
+			resp.RenderError(w, r, resp.NewError(resp.CodeBadRequest, "role must be one of: admin, editor, viewer"))
+			return
+		}
+
+		users, err := userLister.ListUsers()
+		if err != nil {
+			log.Error("failed to list users", sl.Err(err))
+			resp.RenderError(w, r, resp.NewError(resp.CodeInternal, "internal error"))
+			return
+		}
+
+		if role != "" {
+			filtered := make([]storage.User, 0, len(users))
+			for _, u := range users {
+				if u.Role == role {
+					filtered = append(filtered, u)
+				}
+			}
+			users = filtered
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Users:    users,
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package list_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"URLite/internal/http-server/handlers/user/list"
+	"URLite/internal/http-server/handlers/user/list/mocks"
+	"URLite/internal/lib/logger/handlers/slogdiscard"
+	"URLite/internal/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListHandler(t *testing.T) {
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	users := []storage.User{
+		{ID: 1, Username: "root", PasswordHash: "$2a$10$root-hash", Role: storage.RoleAdmin, CreatedAt: createdAt},
+		{ID: 2, Username: "alice", PasswordHash: "$2a$10$alice-hash", Role: storage.RoleEditor, CreatedAt: createdAt},
+		{ID: 3, Username: "bob", PasswordHash: "$2a$10$bob-hash", Role: storage.RoleEditor, CreatedAt: createdAt},
+		{ID: 4, Username: "carol", PasswordHash: "$2a$10$carol-hash", Role: storage.RoleViewer, CreatedAt: createdAt},
+	}
+
+	cases := []struct {
+		name      string
+		query     string
+		list      bool     // Должен ли быть запрошен список пользователей
+		usernames []string // Пользователи в ответе
+		code      int
+		respError string
+		mockError error
+	}{
+		{
+			name:      "All users",
+			list:      true,
+			usernames: []string{"root", "alice", "bob", "carol"},
+			code:      http.StatusOK,
+		},
+		{
+			name:      "Editors",
+			query:     "?role=editor",
+			list:      true,
+			usernames: []string{"alice", "bob"},
+			code:      http.StatusOK,
+		},
+		{
+			name:      "Viewers",
+			query:     "?role=viewer",
+			list:      true,
+			usernames: []string{"carol"},
+			code:      http.StatusOK,
+		},
+		{
+			name:      "Unknown role",
+			query:     "?role=root",
+			code:      http.StatusBadRequest,
+			respError: "role must be one of: admin, editor, viewer",
+		},
+		{
+			name:      "ListUsers Error",
+			list:      true,
+			code:      http.StatusInternalServerError,
+			respError: "internal error",
+			mockError: errors.New("unexpected error"),
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			userListerMock := mocks.NewUserLister(t)
+			if tc.list {
+				var result []storage.User
+				if tc.mockError == nil {
+					result = users
+				}
+				userListerMock.On("ListUsers").Return(result, tc.mockError).Once()
+			}
+
+			handler := list.New(slogdiscard.NewDiscardLogger(), userListerMock)
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/users"+tc.query, nil)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.code, rr.Code)
+
+			var resp list.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tc.respError, resp.Error)
+
+			var usernames []string
+			for _, u := range resp.Users {
+				usernames = append(usernames, u.Username)
+			}
+			require.Equal(t, tc.usernames, usernames)
+		})
+	}
+}
+
+func TestListHandler_NoPasswordHash(t *testing.T) {
+	userListerMock := mocks.NewUserLister(t)
+	userListerMock.On("ListUsers").Return([]storage.User{
+		{ID: 1, Username: "alice", PasswordHash: "$2a$10$alice-hash", Role: storage.RoleEditor},
+	}, nil).Once()
+
+	handler := list.New(slogdiscard.NewDiscardLogger(), userListerMock)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/users", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NotContains(t, rr.Body.String(), "alice-hash")
+
+	var resp struct {
+		Users []map[string]any `json:"users"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Len(t, resp.Users, 1)
+
+	// Пользователь описывается только открытыми полями
+	fields := make([]string, 0, len(resp.Users[0]))
+	for f := range resp.Users[0] {
+		fields = append(fields, f)
+	}
+	require.ElementsMatch(t, []string{"id", "username", "role", "created_at"}, fields)
+}
@@ -0,0 +1,188 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"log/slog"
+
+	resp "URLite/internal/lib/api/response"
+	"URLite/internal/lib/apikey"
+	"URLite/internal/lib/logger/sl"
+	"URLite/internal/lib/password"
+	"URLite/internal/storage"
+)
+
+// realm сообщается клиентам, не прошедшим аутентификацию по паролю.
+const realm = "url-shortener"
+
+// KeyOwnerPrefix отделяет владельцев ключей доступа от пользователей: ссылки ключа с владельцем
+// "team-a" принадлежат "key:team-a". Имя пользователя не может содержать ':', поэтому ключ
+// с владельцем, совпадающим с именем пользователя, не получает доступа к его ссылкам.
+const KeyOwnerPrefix = "key:"
+
+// Principal — клиент API, прошедший аутентификацию.
+type Principal struct {
+	// Owner — владелец ссылок, которые создаёт клиент.
+	Owner string
+	// Role — роль клиента: storage.RoleViewer, storage.RoleEditor или storage.RoleAdmin.
+	Role string
+	// APIKeyID — ключ, которым аутентифицирован клиент. 0 — вход по паролю.
+	APIKeyID int64
+}
+
+// roleRanks упорядочивает роли по возрастанию прав.
+var roleRanks = map[string]int{
+	storage.RoleViewer: 1,
+	storage.RoleEditor: 2,
+	storage.RoleAdmin:  3,
+}
+
+// HasRole сообщает, есть ли у клиента права роли role или более широкие.
+func (p Principal) HasRole(role string) bool {
+	rank, ok := roleRanks[role]
+
+	return ok && roleRanks[p.Role] >= rank
+}
+
+// CanManage сообщает, может ли клиент изменять и удалять ссылки владельца owner.
+// Администратор управляет всеми ссылками, редактор — только своими.
+func (p Principal) CanManage(owner string) bool {
+	if p.HasRole(storage.RoleAdmin) {
+		return true
+	}
+
+	return p.HasRole(storage.RoleEditor) && p.Owner != "" && p.Owner == owner
+}
+
+type principalKey struct{}
+
+// WithPrincipal возвращает копию ctx с клиентом p.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext возвращает клиента, сохранённого в ctx middleware New.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+
+	return p, ok
+}
+
+// APIKeyGetter — интерфейс для поиска ключа доступа по его хешу.
+//
+//go:generate go run github.com/vektra/mockery/v2@v2.28.2 --name=APIKeyGetter
+type APIKeyGetter interface {
+	GetAPIKey(hash string) (storage.APIKey, error)
+}
+
+// UserGetter — интерфейс для поиска пользователя по имени.
+//
+//go:generate go run github.com/vektra/mockery/v2@v2.28.2 --name=UserGetter
+type UserGetter interface {
+	GetUser(username string) (storage.User, error)
+}
+
+// New возвращает middleware, которое пропускает только запросы с действующим ключом
+// в заголовке "Authorization: Bearer <key>" или с логином и паролем пользователя.
+func New(log *slog.Logger, keys APIKeyGetter, users UserGetter) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		log := log.With(
+			slog.String("component", "middleware/auth"),
+		)
+
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			log := log.With(
+				slog.String("request_id", middleware.GetReqID(r.Context())),
+			)
+
+			if key, ok := bearerToken(r); ok {
+				k, err := keys.GetAPIKey(apikey.Hash(key))
+				if errors.Is(err, storage.ErrAPIKeyNotFound) || (err == nil && k.Revoked()) {
+					log.Info("invalid api key", slog.String("prefix", apikey.Prefix(key)))
+					unauthorized(w, r)
+					return
+				}
+				if err != nil {
+					log.Error("failed to get api key", sl.Err(err))
+					internalError(w, r)
+					return
+				}
+
+				p := Principal{Owner: KeyOwnerPrefix + k.Owner, Role: k.Role, APIKeyID: k.ID}
+				next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), p)))
+				return
+			}
+
+			if username, pass, ok := r.BasicAuth(); ok {
+				u, err := users.GetUser(username)
+				if errors.Is(err, storage.ErrUserNotFound) {
+					password.CompareDummy(pass)
+					log.Info("unknown user", slog.String("user", username))
+					unauthorized(w, r)
+					return
+				}
+				if err != nil {
+					log.Error("failed to get user", sl.Err(err))
+					internalError(w, r)
+					return
+				}
+
+				if !password.Compare(u.PasswordHash, pass) {
+					log.Info("invalid password", slog.String("user", username))
+					unauthorized(w, r)
+					return
+				}
+
+				p := Principal{Owner: u.Username, Role: u.Role}
+				next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), p)))
+				return
+			}
+
+			unauthorized(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// RequireRole пропускает только запросы клиентов с ролью role или более широкой. Должно стоять после New.
+func RequireRole(role string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			p, ok := PrincipalFromContext(r.Context())
+			if !ok || !p.HasRole(role) {
+				resp.RenderError(w, r, resp.NewError(resp.CodeForbidden, "forbidden"))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	const scheme = "Bearer "
+
+	header := r.Header.Get("Authorization")
+	if len(header) <= len(scheme) || !strings.EqualFold(header[:len(scheme)], scheme) {
+		return "", false
+	}
+
+	return strings.TrimSpace(header[len(scheme):]), true
+}
+
+func internalError(w http.ResponseWriter, r *http.Request) {
+	resp.RenderError(w, r, resp.NewError(resp.CodeInternal, "internal error"))
+}
+
+func unauthorized(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("WWW-Authenticate", "Bearer")
+	w.Header().Add("WWW-Authenticate", `Basic realm="`+realm+`"`)
+	resp.RenderError(w, r, resp.NewError(resp.CodeUnauthorized, "unauthorized"))
+}
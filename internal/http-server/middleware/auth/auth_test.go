@@ -0,0 +1,174 @@
+package auth_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"URLite/internal/http-server/middleware/auth"
+	"URLite/internal/http-server/middleware/auth/mocks"
+	"URLite/internal/lib/apikey"
+	"URLite/internal/lib/logger/handlers/slogdiscard"
+	"URLite/internal/lib/password"
+	"URLite/internal/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	const key = "ulk_0123456789abcdef"
+
+	hash, err := password.Hash("secret")
+	require.NoError(t, err)
+
+	revokedAt := time.Now()
+
+	cases := []struct {
+		name      string
+		setup     func(r *http.Request)
+		apiKey    *storage.APIKey
+		user      *storage.User
+		mockError error
+		code      int
+		principal auth.Principal
+	}{
+		{
+			name:      "API Key",
+			setup:     func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+key) },
+			apiKey:    &storage.APIKey{ID: 7, Owner: "team-a", Role: storage.RoleEditor},
+			code:      http.StatusOK,
+			principal: auth.Principal{Owner: "key:team-a", Role: storage.RoleEditor, APIKeyID: 7},
+		},
+		{
+			name:   "Revoked API Key",
+			setup:  func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+key) },
+			apiKey: &storage.APIKey{ID: 7, Owner: "team-a", RevokedAt: &revokedAt},
+			code:   http.StatusUnauthorized,
+		},
+		{
+			name:      "Unknown API Key",
+			setup:     func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+key) },
+			apiKey:    &storage.APIKey{},
+			mockError: storage.ErrAPIKeyNotFound,
+			code:      http.StatusUnauthorized,
+		},
+		{
+			name:      "API Key Storage Error",
+			setup:     func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+key) },
+			apiKey:    &storage.APIKey{},
+			mockError: errors.New("unexpected error"),
+			code:      http.StatusInternalServerError,
+		},
+		{
+			name:      "User Password",
+			setup:     func(r *http.Request) { r.SetBasicAuth("alice", "secret") },
+			user:      &storage.User{Username: "alice", PasswordHash: hash, Role: storage.RoleViewer},
+			code:      http.StatusOK,
+			principal: auth.Principal{Owner: "alice", Role: storage.RoleViewer},
+		},
+		{
+			name:  "Wrong Password",
+			setup: func(r *http.Request) { r.SetBasicAuth("alice", "wrong") },
+			user:  &storage.User{Username: "alice", PasswordHash: hash, Role: storage.RoleViewer},
+			code:  http.StatusUnauthorized,
+		},
+		{
+			name:      "Unknown User",
+			setup:     func(r *http.Request) { r.SetBasicAuth("alice", "secret") },
+			user:      &storage.User{},
+			mockError: storage.ErrUserNotFound,
+			code:      http.StatusUnauthorized,
+		},
+		{
+			name:      "User Storage Error",
+			setup:     func(r *http.Request) { r.SetBasicAuth("alice", "secret") },
+			user:      &storage.User{},
+			mockError: errors.New("unexpected error"),
+			code:      http.StatusInternalServerError,
+		},
+		{
+			name:  "No Credentials",
+			setup: func(r *http.Request) {},
+			code:  http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			keysMock := mocks.NewAPIKeyGetter(t)
+			if tc.apiKey != nil {
+				keysMock.On("GetAPIKey", apikey.Hash(key)).Return(*tc.apiKey, tc.mockError).Once()
+			}
+
+			usersMock := mocks.NewUserGetter(t)
+			if tc.user != nil {
+				usersMock.On("GetUser", "alice").Return(*tc.user, tc.mockError).Once()
+			}
+
+			var got auth.Principal
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, _ = auth.PrincipalFromContext(r.Context())
+			})
+
+			handler := auth.New(slogdiscard.NewDiscardLogger(), keysMock, usersMock)(next)
+
+			req := httptest.NewRequest(http.MethodGet, "/url", nil)
+			tc.setup(req)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.code, rr.Code)
+			require.Equal(t, tc.principal, got)
+
+			if tc.code == http.StatusUnauthorized {
+				require.NotEmpty(t, rr.Header().Values("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	cases := []struct {
+		name      string
+		role      string
+		principal *auth.Principal
+		code      int
+	}{
+		{name: "Admin as editor", role: storage.RoleEditor, principal: &auth.Principal{Role: storage.RoleAdmin}, code: http.StatusOK},
+		{name: "Editor as editor", role: storage.RoleEditor, principal: &auth.Principal{Role: storage.RoleEditor}, code: http.StatusOK},
+		{name: "Viewer as editor", role: storage.RoleEditor, principal: &auth.Principal{Role: storage.RoleViewer}, code: http.StatusForbidden},
+		{name: "Editor as admin", role: storage.RoleAdmin, principal: &auth.Principal{Role: storage.RoleEditor}, code: http.StatusForbidden},
+		{name: "Unknown role", role: storage.RoleViewer, principal: &auth.Principal{Role: "guest"}, code: http.StatusForbidden},
+		{name: "Anonymous", role: storage.RoleViewer, code: http.StatusForbidden},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/url", nil)
+			if tc.principal != nil {
+				req = req.WithContext(auth.WithPrincipal(req.Context(), *tc.principal))
+			}
+
+			rr := httptest.NewRecorder()
+			auth.RequireRole(tc.role)(next).ServeHTTP(rr, req)
+
+			require.Equal(t, tc.code, rr.Code)
+		})
+	}
+}
+
+func TestPrincipal_CanManage(t *testing.T) {
+	require.True(t, auth.Principal{Role: storage.RoleAdmin}.CanManage("team-a"))
+	require.True(t, auth.Principal{Owner: "team-a", Role: storage.RoleEditor}.CanManage("team-a"))
+	require.False(t, auth.Principal{Owner: "team-b", Role: storage.RoleEditor}.CanManage("team-a"))
+	require.False(t, auth.Principal{Owner: "team-a", Role: storage.RoleViewer}.CanManage("team-a"))
+	require.False(t, auth.Principal{Role: storage.RoleEditor}.CanManage(""))
+}
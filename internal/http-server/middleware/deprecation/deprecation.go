@@ -0,0 +1,20 @@
+package deprecation
+
+import (
+	"net/http"
+)
+
+// New возвращает middleware для устаревших маршрутов: ответ получает заголовок
+// "Deprecation: true" и ссылку на тот же путь с префиксом successorPrefix.
+func New(successorPrefix string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", "true")
+			w.Header().Set("Link", "<"+successorPrefix+r.URL.Path+`>; rel="successor-version"`)
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
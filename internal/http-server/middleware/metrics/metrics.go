@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// unmatchedRoute — метка запросов, для которых не нашлось маршрута.
+const unmatchedRoute = "unmatched"
+
+// Observer — получатель метрик HTTP-запросов.
+type Observer interface {
+	ObserveHTTPRequest(route, method string, status int, d time.Duration)
+}
+
+// New возвращает middleware, которое учитывает каждый запрос с меткой шаблона маршрута chi
+// (например, "/{alias}"), а не фактического пути, чтобы число временных рядов не росло с числом ссылок.
+func New(observer Observer) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			t1 := time.Now()
+			defer func() {
+				route := unmatchedRoute
+				if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+					route = rctx.RoutePattern()
+				}
+
+				status := ww.Status()
+				if status == 0 {
+					status = http.StatusOK
+				}
+
+				observer.ObserveHTTPRequest(route, r.Method, status, time.Since(t1))
+			}()
+
+			next.ServeHTTP(ww, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
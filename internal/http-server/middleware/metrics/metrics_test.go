@@ -0,0 +1,51 @@
+package metrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mwMetrics "URLite/internal/http-server/middleware/metrics"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+type observation struct {
+	route  string
+	method string
+	status int
+}
+
+type observerStub struct {
+	observations []observation
+}
+
+func (o *observerStub) ObserveHTTPRequest(route, method string, status int, _ time.Duration) {
+	o.observations = append(o.observations, observation{route: route, method: method, status: status})
+}
+
+func TestMetricsMiddleware(t *testing.T) {
+	observer := &observerStub{}
+
+	router := chi.NewRouter()
+	router.Use(mwMetrics.New(observer))
+	router.Get("/{alias}", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://go.dev", http.StatusFound)
+	})
+	router.Route("/url", func(r chi.Router) {
+		r.Get("/{alias}/stats", func(w http.ResponseWriter, r *http.Request) {})
+	})
+
+	for _, path := range []string{"/first", "/second", "/url/first/stats", "/url/first/unknown/path", "/first/unknown"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	require.Equal(t, []observation{
+		{route: "/{alias}", method: http.MethodGet, status: http.StatusFound},
+		{route: "/{alias}", method: http.MethodGet, status: http.StatusFound},
+		{route: "/url/{alias}/stats", method: http.MethodGet, status: http.StatusOK},
+		{route: "/url/*", method: http.MethodGet, status: http.StatusNotFound},
+		{route: "unmatched", method: http.MethodGet, status: http.StatusNotFound},
+	}, observer.observations)
+}
@@ -0,0 +1,167 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"log/slog"
+
+	"URLite/internal/http-server/middleware/auth"
+	resp "URLite/internal/lib/api/response"
+)
+
+// KeyFunc возвращает ключ клиента, по которому считаются запросы.
+type KeyFunc func(r *http.Request) string
+
+// New возвращает middleware, которое отвечает 429 Too Many Requests с заголовком Retry-After,
+// когда клиент исчерпал свою корзину в limiter.
+func New(log *slog.Logger, limiter *Limiter, key KeyFunc) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		log := log.With(
+			slog.String("component", "middleware/ratelimit"),
+		)
+
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			k := key(r)
+
+			ok, wait := limiter.Allow(k, time.Now())
+			if !ok {
+				log.Info("rate limit exceeded",
+					slog.String("client", k),
+					slog.String("request_id", middleware.GetReqID(r.Context())),
+				)
+				tooManyRequests(w, r, wait)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// FailedAuth возвращает middleware, которое ограничивает неудачные попытки входа: ставится перед auth.New
+// и отвечает 429, когда клиент исчерпал свою корзину в limiter, ещё до проверки пароля.
+// Каждый запрос заранее забирает маркер, а если ответ не 401 Unauthorized, маркер возвращается.
+// Так учитываются только неудачные попытки, и параллельные запросы не обходят лимит.
+func FailedAuth(log *slog.Logger, limiter *Limiter, key KeyFunc) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		log := log.With(
+			slog.String("component", "middleware/ratelimit"),
+		)
+
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			k := key(r)
+
+			ok, wait := limiter.Allow(k, time.Now())
+			if !ok {
+				log.Info("too many failed auth attempts",
+					slog.String("client", k),
+					slog.String("request_id", middleware.GetReqID(r.Context())),
+				)
+				tooManyRequests(w, r, wait)
+				return
+			}
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			if ww.Status() != http.StatusUnauthorized {
+				limiter.Refund(k)
+			}
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+func tooManyRequests(w http.ResponseWriter, r *http.Request, wait time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	resp.RenderError(w, r, resp.NewError(resp.CodeTooManyRequests, "too many requests"))
+}
+
+// ClientKey возвращает KeyFunc, который различает клиентов по ключу доступа,
+// а остальных — по IP-адресу. Для ключа доступа middleware должно стоять после auth.New.
+func ClientKey(trustedProxies []netip.Prefix) KeyFunc {
+	return func(r *http.Request) string {
+		if p, ok := auth.PrincipalFromContext(r.Context()); ok && p.APIKeyID != 0 {
+			return "key:" + strconv.FormatInt(p.APIKeyID, 10)
+		}
+
+		return "ip:" + ClientIP(r, trustedProxies)
+	}
+}
+
+// ClientIP возвращает адрес клиента. X-Forwarded-For учитывается, только если запрос пришёл
+// от доверенного прокси: адреса в заголовке просматриваются справа налево, пока не встретится
+// недоверенный — его и нельзя подделать, дописав заголовок на стороне клиента.
+func ClientIP(r *http.Request, trustedProxies []netip.Prefix) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !trusted(addr, trustedProxies) {
+		return host
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break
+		}
+
+		addr = hop.Unmap()
+		if !trusted(addr, trustedProxies) {
+			break
+		}
+	}
+
+	return addr.String()
+}
+
+// ParseTrustedProxies разбирает список адресов и подсетей в нотации CIDR.
+func ParseTrustedProxies(proxies []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+
+	for _, p := range proxies {
+		if strings.Contains(p, "/") {
+			prefix, err := netip.ParsePrefix(p)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", p, err)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", p, err)
+		}
+		prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+	}
+
+	return prefixes, nil
+}
+
+func trusted(addr netip.Addr, trustedProxies []netip.Prefix) bool {
+	addr = addr.Unmap()
+
+	for _, p := range trustedProxies {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
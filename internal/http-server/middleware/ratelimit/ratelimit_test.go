@@ -0,0 +1,210 @@
+package ratelimit_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"URLite/internal/http-server/middleware/auth"
+	"URLite/internal/http-server/middleware/ratelimit"
+	"URLite/internal/lib/api/response"
+	"URLite/internal/lib/logger/handlers/slogdiscard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	l := ratelimit.NewLimiter(2, 3)
+	now := time.Now()
+
+	for i := 0; i < 3; i++ {
+		ok, _ := l.Allow("a", now)
+		require.True(t, ok, "request %d", i)
+	}
+
+	ok, wait := l.Allow("a", now)
+	require.False(t, ok)
+	assert.Equal(t, 500*time.Millisecond, wait)
+
+	// У другого клиента своя корзина
+	ok, _ = l.Allow("b", now)
+	require.True(t, ok)
+
+	// За полсекунды при скорости 2 маркера в секунду появляется один маркер
+	ok, _ = l.Allow("a", now.Add(500*time.Millisecond))
+	require.True(t, ok)
+	ok, _ = l.Allow("a", now.Add(500*time.Millisecond))
+	require.False(t, ok)
+
+	// Корзина не наполняется больше burst
+	for i := 0; i < 3; i++ {
+		ok, _ := l.Allow("a", now.Add(time.Hour))
+		require.True(t, ok, "request %d", i)
+	}
+	ok, _ = l.Allow("a", now.Add(time.Hour))
+	require.False(t, ok)
+}
+
+func TestLimiter_Disabled(t *testing.T) {
+	// Отрицательная частота приходит из конфигурации, где она отключает ограничение
+	l := ratelimit.NewLimiter(-1, 0)
+
+	for i := 0; i < 100; i++ {
+		ok, _ := l.Allow("a", time.Now())
+		require.True(t, ok)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	limiter := ratelimit.NewLimiter(1, 2)
+	handler := ratelimit.New(slogdiscard.NewDiscardLogger(), limiter, ratelimit.ClientKey(nil))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+
+	do := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/go", nil)
+		req.RemoteAddr = remoteAddr
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	require.Equal(t, http.StatusOK, do("192.0.2.1:1000").Code)
+	require.Equal(t, http.StatusOK, do("192.0.2.1:1001").Code)
+
+	rr := do("192.0.2.1:1002")
+	require.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "1", rr.Header().Get("Retry-After"))
+
+	var resp response.Response
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, response.StatusError, resp.Status)
+	assert.Equal(t, "too many requests", resp.Error)
+
+	// Другой адрес ограничивается отдельно
+	require.Equal(t, http.StatusOK, do("192.0.2.2:1000").Code)
+}
+
+func TestFailedAuth(t *testing.T) {
+	limiter := ratelimit.NewLimiter(1, 2)
+	handler := ratelimit.FailedAuth(slogdiscard.NewDiscardLogger(), limiter, ratelimit.ClientKey(nil))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, pass, _ := r.BasicAuth(); pass != "secret-pass" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+
+	do := func(remoteAddr, pass string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/url", nil)
+		req.RemoteAddr = remoteAddr
+		req.SetBasicAuth("admin", pass)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// Успешные входы не расходуют попытки
+	for i := 0; i < 5; i++ {
+		require.Equal(t, http.StatusOK, do("192.0.2.1:1000", "secret-pass").Code, "request %d", i)
+	}
+
+	require.Equal(t, http.StatusUnauthorized, do("192.0.2.1:1000", "wrong").Code)
+	require.Equal(t, http.StatusUnauthorized, do("192.0.2.1:1000", "wrong").Code)
+
+	// Попытки исчерпаны: следующий запрос отклоняется до проверки пароля, даже с верным паролем
+	rr := do("192.0.2.1:1000", "secret-pass")
+	require.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "1", rr.Header().Get("Retry-After"))
+
+	// Другой адрес ограничивается отдельно
+	require.Equal(t, http.StatusUnauthorized, do("192.0.2.2:1000", "wrong").Code)
+}
+
+func TestClientKey_APIKey(t *testing.T) {
+	key := ratelimit.ClientKey(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/url", nil)
+	req.RemoteAddr = "192.0.2.1:1000"
+	assert.Equal(t, "ip:192.0.2.1", key(req))
+
+	req = req.WithContext(auth.WithPrincipal(req.Context(), auth.Principal{Owner: "team-a", APIKeyID: 7}))
+	assert.Equal(t, "key:7", key(req))
+}
+
+func TestClientIP(t *testing.T) {
+	trusted, err := ratelimit.ParseTrustedProxies([]string{"10.0.0.0/8", "192.0.2.10"})
+	require.NoError(t, err)
+
+	cases := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		expectedIP   string
+	}{
+		{
+			name:       "Direct client",
+			remoteAddr: "198.51.100.1:1000",
+			expectedIP: "198.51.100.1",
+		},
+		{
+			name:         "Untrusted peer cannot spoof",
+			remoteAddr:   "198.51.100.1:1000",
+			forwardedFor: []string{"203.0.113.5"},
+			expectedIP:   "198.51.100.1",
+		},
+		{
+			name:         "Trusted proxy",
+			remoteAddr:   "10.1.2.3:1000",
+			forwardedFor: []string{"203.0.113.5"},
+			expectedIP:   "203.0.113.5",
+		},
+		{
+			name:         "Chain of trusted proxies",
+			remoteAddr:   "10.1.2.3:1000",
+			forwardedFor: []string{"1.1.1.1, 203.0.113.5", "192.0.2.10"},
+			expectedIP:   "203.0.113.5",
+		},
+		{
+			name:         "Malformed header",
+			remoteAddr:   "10.1.2.3:1000",
+			forwardedFor: []string{"garbage"},
+			expectedIP:   "10.1.2.3",
+		},
+		{
+			name:       "Trusted proxy without header",
+			remoteAddr: "10.1.2.3:1000",
+			expectedIP: "10.1.2.3",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/go", nil)
+			req.RemoteAddr = tc.remoteAddr
+			for _, v := range tc.forwardedFor {
+				req.Header.Add("X-Forwarded-For", v)
+			}
+
+			assert.Equal(t, tc.expectedIP, ratelimit.ClientIP(req, trusted))
+		})
+	}
+}
+
+func TestParseTrustedProxies_Invalid(t *testing.T) {
+	_, err := ratelimit.ParseTrustedProxies([]string{"10.0.0.0/99"})
+	require.Error(t, err)
+
+	_, err = ratelimit.ParseTrustedProxies([]string{"proxy.local"})
+	require.Error(t, err)
+}
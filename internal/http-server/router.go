@@ -0,0 +1,197 @@
+package httpserver
+
+import (
+	"URLite/internal/config"
+	keyCreate "URLite/internal/http-server/handlers/apikey/create"
+	keyList "URLite/internal/http-server/handlers/apikey/list"
+	keyRevoke "URLite/internal/http-server/handlers/apikey/revoke"
+	"URLite/internal/http-server/handlers/delete"
+	"URLite/internal/http-server/handlers/health"
+	"URLite/internal/http-server/handlers/openapi"
+	"URLite/internal/http-server/handlers/redirect"
+	"URLite/internal/http-server/handlers/stats"
+	"URLite/internal/http-server/handlers/url/info"
+	"URLite/internal/http-server/handlers/url/list"
+	"URLite/internal/http-server/handlers/url/save"
+	"URLite/internal/http-server/handlers/url/update"
+	userCreate "URLite/internal/http-server/handlers/user/create"
+	userDelete "URLite/internal/http-server/handlers/user/delete"
+	userList "URLite/internal/http-server/handlers/user/list"
+	"URLite/internal/http-server/middleware/auth"
+	"URLite/internal/http-server/middleware/deprecation"
+	mwLogger "URLite/internal/http-server/middleware/logger"
+	mwMetrics "URLite/internal/http-server/middleware/metrics"
+	"URLite/internal/http-server/middleware/ratelimit"
+	"URLite/internal/lib/alias"
+	"URLite/internal/lib/urlnorm"
+	"URLite/internal/metrics"
+	"URLite/internal/storage"
+	"URLite/internal/storage/cache"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// APIPrefix — префикс текущей версии API управления ссылками.
+const APIPrefix = "/api/v1"
+
+// Storage — хранилище, которое используют обработчики API.
+type Storage interface {
+	list.URLLister
+	info.URLInfoGetter
+	stats.StatsGetter
+	auth.APIKeyGetter
+	auth.UserGetter
+	keyCreate.APIKeySaver
+	keyList.APIKeyLister
+	keyRevoke.APIKeyRevoker
+	userCreate.UserSaver
+	userList.UserLister
+	userDelete.UserDeleter
+}
+
+// Deps — зависимости обработчиков HTTP-сервера.
+type Deps struct {
+	Storage Storage
+	// URLCache обслуживает редиректы, и через него проходят все изменения ссылок.
+	URLCache        *cache.Cache
+	AliasGenerator  save.AliasGenerator
+	ClickRecorder   redirect.ClickRecorder
+	Metrics         *metrics.Metrics
+	HealthState     *health.State
+	ReadinessChecks map[string]health.Checker
+}
+
+// NewRouter возвращает маршрутизатор сервиса. API управления доступно под APIPrefix,
+// а маршруты ссылок, существовавшие до версионирования, и по старым путям без префикса —
+// с заголовком Deprecation. Служебные маршруты и редиректы
+// остаются в корне. Каждый маршрут должен быть описан в спецификации OpenAPI,
+// это проверяет TestRouter_MatchesOpenAPI. Первые сегменты путей всех маршрутов
+// зарезервированы и не могут быть псевдонимами ссылок.
+func NewRouter(log *slog.Logger, cfg *config.Config, deps Deps) (*chi.Mux, error) {
+	router := chi.NewRouter()
+
+	// middleware
+
+	router.Use(middleware.RequestID)
+	router.Use(middleware.Logger)
+	router.Use(mwLogger.New(log))
+	router.Use(mwMetrics.New(deps.Metrics))
+	router.Use(middleware.Recoverer)
+
+	router.Get("/healthz", health.NewLiveness())
+	router.Get("/readyz", health.NewReadiness(log, deps.HealthState, deps.ReadinessChecks))
+	router.Get("/metrics", deps.Metrics.Handler().ServeHTTP)
+	router.Get("/openapi.json", openapi.New())
+
+	trustedProxies, err := ratelimit.ParseTrustedProxies(cfg.RateLimit.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
+	// Лимит на запись стоит после аутентификации, чтобы клиенты с ключом доступа ограничивались по ключу,
+	// а лимит неудачных входов — до неё: проверка пароля дорогая, и перебор нужно остановить раньше
+	clientKey := ratelimit.ClientKey(trustedProxies)
+	limitWrites := ratelimit.New(log, ratelimit.NewLimiter(cfg.RateLimit.WriteRate, cfg.RateLimit.WriteBurst), clientKey)
+	limitAuth := ratelimit.FailedAuth(log, ratelimit.NewLimiter(cfg.RateLimit.AuthRate, cfg.RateLimit.AuthBurst), clientKey)
+	limitRedirects := ratelimit.New(log, ratelimit.NewLimiter(cfg.RateLimit.RedirectRate, cfg.RateLimit.RedirectBurst), clientKey)
+
+	aliasRules := alias.NewRules(alias.RulesOptions{
+		MinLength: cfg.Alias.Custom.MinLength,
+		MaxLength: cfg.Alias.Custom.MaxLength,
+		Lowercase: cfg.Alias.Custom.Lowercase,
+		Reserved:  cfg.Alias.Custom.Reserved,
+		Blocklist: cfg.Alias.Blocklist,
+	})
+
+	normalizer := urlnorm.New(urlnorm.Options{
+		SortQuery:      cfg.URLNormalization.SortQuery,
+		StripTracking:  cfg.URLNormalization.StripTracking,
+		TrackingParams: cfg.URLNormalization.TrackingParams,
+	})
+
+	urls, admin := apiRoutes(log, deps, limitAuth, limitWrites, aliasRules, normalizer)
+
+	router.Route(APIPrefix, func(r chi.Router) {
+		r.Route("/url", urls)
+		admin(r)
+	})
+
+	// Старые пути без версии оставлены для существующих клиентов. Административные маршруты
+	// появились уже под APIPrefix, поэтому без префикса не регистрируются
+	router.Group(func(r chi.Router) {
+		r.Use(deprecation.New(APIPrefix))
+		r.Route("/url", urls)
+	})
+
+	router.With(limitRedirects).Get("/{alias}", redirect.New(log, deps.URLCache, deps.ClickRecorder, trustedProxies))
+
+	// Ссылка с псевдонимом, совпадающим с маршрутом, была бы недоступна или перекрыла бы его
+	err = chi.Walk(router, func(_, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		segment, _, _ := strings.Cut(strings.TrimPrefix(route, "/"), "/")
+		if segment != "" && !strings.HasPrefix(segment, "{") {
+			aliasRules.Reserve(segment)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return router, nil
+}
+
+// apiRoutes возвращает функции, которые регистрируют маршруты управления ссылками
+// относительно "/url" и административные маршруты.
+func apiRoutes(
+	log *slog.Logger,
+	deps Deps,
+	limitAuth func(http.Handler) http.Handler,
+	limitWrites func(http.Handler) http.Handler,
+	aliasRules *alias.Rules,
+	normalizer *urlnorm.Normalizer,
+) (urls, admin func(r chi.Router)) {
+	s, urlCache := deps.Storage, deps.URLCache
+
+	// Клиенты входят по ключу доступа или по логину и паролю пользователя
+	authenticate := auth.New(log, s, s)
+	requireViewer := auth.RequireRole(storage.RoleViewer)
+	requireEditor := auth.RequireRole(storage.RoleEditor)
+	requireAdmin := auth.RequireRole(storage.RoleAdmin)
+
+	// Просматривать ссылки может любой пользователь, создавать и изменять — редактор и администратор
+	urls = func(r chi.Router) {
+		r.Use(limitAuth, authenticate)
+
+		r.With(requireEditor, limitWrites).Post("/", save.New(log, urlCache, deps.AliasGenerator, aliasRules, normalizer))
+		r.With(requireViewer).Get("/", list.New(log, s))
+		r.With(requireViewer).Get("/{alias}", info.New(log, s))
+		r.With(requireViewer).Get("/{alias}/stats", stats.New(log, s))
+		r.With(requireEditor, limitWrites).Patch("/{alias}", update.New(log, urlCache, normalizer))
+		r.With(requireEditor, limitWrites).Delete("/{alias}", delete.New(log, urlCache))
+	}
+
+	admin = func(r chi.Router) {
+		r.Route("/admin/keys", func(r chi.Router) {
+			r.Use(limitAuth, authenticate)
+			r.Use(requireAdmin)
+
+			r.Post("/", keyCreate.New(log, s))
+			r.Get("/", keyList.New(log, s))
+			r.Delete("/{id}", keyRevoke.New(log, s))
+		})
+
+		r.Route("/admin/users", func(r chi.Router) {
+			r.Use(limitAuth, authenticate)
+			r.Use(requireAdmin)
+
+			r.Post("/", userCreate.New(log, s))
+			r.Get("/", userList.New(log, s))
+			r.Delete("/{username}", userDelete.New(log, s))
+		})
+	}
+
+	return urls, admin
+}
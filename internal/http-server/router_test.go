@@ -0,0 +1,319 @@
+package httpserver_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"URLite/internal/analytics"
+	"URLite/internal/config"
+	httpserver "URLite/internal/http-server"
+	"URLite/internal/http-server/handlers/health"
+	"URLite/internal/http-server/handlers/openapi"
+	"URLite/internal/lib/alias"
+	"URLite/internal/lib/logger/handlers/slogdiscard"
+	"URLite/internal/lib/password"
+	"URLite/internal/metrics"
+	"URLite/internal/storage"
+	"URLite/internal/storage/cache"
+	"URLite/internal/storage/memory"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRouter(t *testing.T) *chi.Mux {
+	t.Helper()
+
+	return newTestRouterWithConfig(t, &config.Config{})
+}
+
+func newTestRouterWithConfig(t *testing.T, cfg *config.Config) *chi.Mux {
+	t.Helper()
+
+	log := slogdiscard.NewDiscardLogger()
+	s := memory.New()
+
+	hash, err := password.Hash("secret-pass")
+	require.NoError(t, err)
+
+	_, err = s.SaveUser(storage.User{Username: "admin", PasswordHash: hash, Role: storage.RoleAdmin})
+	require.NoError(t, err)
+
+	router, err := httpserver.NewRouter(log, cfg, httpserver.Deps{
+		Storage:         s,
+		URLCache:        cache.New(s, cache.Options{}),
+		AliasGenerator:  alias.NewRandom(alias.DefaultLength, alias.DefaultMaxLength),
+		ClickRecorder:   analytics.NewRecorder(log, s, analytics.Options{}),
+		Metrics:         metrics.New(),
+		HealthState:     &health.State{},
+		ReadinessChecks: map[string]health.Checker{},
+	})
+	require.NoError(t, err)
+
+	return router
+}
+
+// TestRouter_MatchesOpenAPI проверяет, что спецификация описывает ровно те маршруты,
+// которые зарегистрированы в маршрутизаторе.
+func TestRouter_MatchesOpenAPI(t *testing.T) {
+	var registered []string
+	err := chi.Walk(newTestRouter(t), func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		// Маршруты "/" вложенных маршрутизаторов chi сообщает с завершающей косой чертой
+		if len(route) > 1 {
+			route = strings.TrimSuffix(route, "/")
+		}
+		registered = append(registered, method+" "+route)
+		return nil
+	})
+	require.NoError(t, err)
+
+	var spec struct {
+		OpenAPI string                                `json:"openapi"`
+		Paths   map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(openapi.Spec(), &spec))
+	require.True(t, strings.HasPrefix(spec.OpenAPI, "3."), "unexpected openapi version %q", spec.OpenAPI)
+
+	var documented []string
+	for path, item := range spec.Paths {
+		for method := range item {
+			switch method {
+			case "get", "put", "post", "delete", "options", "head", "patch", "trace":
+				documented = append(documented, strings.ToUpper(method)+" "+path)
+			}
+		}
+	}
+
+	sort.Strings(registered)
+	sort.Strings(documented)
+
+	assert.Equal(t, registered, documented)
+}
+
+func TestRouter_ServesOpenAPI(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	rr := httptest.NewRecorder()
+	newTestRouter(t).ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	assert.JSONEq(t, string(openapi.Spec()), rr.Body.String())
+}
+
+func TestRouter_LegacyPaths(t *testing.T) {
+	router := newTestRouter(t)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.SetBasicAuth("admin", "secret-pass")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := do(http.MethodPost, httpserver.APIPrefix+"/url", `{"url": "https://go.dev", "alias": "go"}`)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	assert.Empty(t, rr.Header().Get("Deprecation"))
+
+	// Ссылка, созданная через v1, видна по старому пути, но ответ помечен как устаревший
+	rr = do(http.MethodGet, "/url/go", "")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Equal(t, "true", rr.Header().Get("Deprecation"))
+	assert.Equal(t, `</api/v1/url/go>; rel="successor-version"`, rr.Header().Get("Link"))
+
+	rr = do(http.MethodDelete, "/url/go", "")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Equal(t, "true", rr.Header().Get("Deprecation"))
+
+	rr = do(http.MethodGet, httpserver.APIPrefix+"/url/go", "")
+	require.Equal(t, http.StatusNotFound, rr.Code)
+
+	// Административные маршруты доступны только под префиксом
+	rr = do(http.MethodGet, "/admin/users", "")
+	require.Equal(t, http.StatusNotFound, rr.Code)
+
+	// Редиректы и служебные маршруты остаются в корне и не считаются устаревшими
+	rr = do(http.MethodGet, "/healthz", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Header().Get("Deprecation"))
+}
+
+// TestRouter_FailedAuthLimit проверяет, что неудачные попытки входа ограничиваются
+// на всех маршрутах с аутентификацией, в том числе административных.
+func TestRouter_FailedAuthLimit(t *testing.T) {
+	for _, path := range []string{"/url", "/admin/users", "/admin/keys"} {
+		path := path
+
+		t.Run(path, func(t *testing.T) {
+			t.Parallel()
+
+			router := newTestRouterWithConfig(t, &config.Config{
+				RateLimit: config.RateLimit{AuthRate: 0.01, AuthBurst: 2},
+			})
+
+			do := func(pass string) int {
+				req := httptest.NewRequest(http.MethodGet, httpserver.APIPrefix+path, nil)
+				req.SetBasicAuth("admin", pass)
+				rr := httptest.NewRecorder()
+				router.ServeHTTP(rr, req)
+				return rr.Code
+			}
+
+			require.Equal(t, http.StatusOK, do("secret-pass"))
+			require.Equal(t, http.StatusUnauthorized, do("wrong-pass"))
+			require.Equal(t, http.StatusUnauthorized, do("wrong-pass"))
+			require.Equal(t, http.StatusTooManyRequests, do("wrong-pass"))
+			require.Equal(t, http.StatusTooManyRequests, do("secret-pass"))
+		})
+	}
+}
+
+func TestRouter_ReuseExisting(t *testing.T) {
+	router := newTestRouter(t)
+
+	save := func() (int, string) {
+		body := `{"url": "https://go.dev/doc", "reuse_existing": true}`
+		req := httptest.NewRequest(http.MethodPost, httpserver.APIPrefix+"/url", strings.NewReader(body))
+		req.SetBasicAuth("admin", "secret-pass")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		var resp struct {
+			Alias string `json:"alias"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+		return rr.Code, resp.Alias
+	}
+
+	code, created := save()
+	require.Equal(t, http.StatusCreated, code)
+
+	code, reused := save()
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, created, reused)
+}
+
+// TestRouter_KeyOwnerNamespace проверяет, что ключ доступа с владельцем, совпадающим
+// с именем пользователя, не может управлять ссылками этого пользователя.
+func TestRouter_KeyOwnerNamespace(t *testing.T) {
+	router := newTestRouter(t)
+
+	do := func(method, path, body string, auth func(r *http.Request)) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, httpserver.APIPrefix+path, strings.NewReader(body))
+		auth(req)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+	asAdmin := func(r *http.Request) { r.SetBasicAuth("admin", "secret-pass") }
+	asAlice := func(r *http.Request) { r.SetBasicAuth("alice", "alice-pass") }
+
+	rr := do(http.MethodPost, "/admin/users", `{"username": "alice", "password": "alice-pass", "role": "editor"}`, asAdmin)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	rr = do(http.MethodPost, "/admin/keys", `{"name": "alice"}`, asAdmin)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	var key struct {
+		Key   string `json:"key"`
+		Owner string `json:"owner"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &key))
+	require.Equal(t, "alice", key.Owner)
+	asKey := func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+key.Key) }
+
+	rr = do(http.MethodPost, "/url", `{"url": "https://go.dev", "alias": "alice-link"}`, asAlice)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	rr = do(http.MethodPatch, "/url/alice-link", `{"url": "https://example.com"}`, asKey)
+	require.Equal(t, http.StatusForbidden, rr.Code, rr.Body.String())
+
+	rr = do(http.MethodDelete, "/url/alice-link", "", asKey)
+	require.Equal(t, http.StatusForbidden, rr.Code, rr.Body.String())
+
+	// И наоборот: ссылки ключа не принадлежат пользователю
+	rr = do(http.MethodPost, "/url", `{"url": "https://go.dev", "alias": "key-link"}`, asKey)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	rr = do(http.MethodDelete, "/url/key-link", "", asAlice)
+	require.Equal(t, http.StatusForbidden, rr.Code, rr.Body.String())
+
+	rr = do(http.MethodGet, "/url/key-link", "", asAlice)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Contains(t, rr.Body.String(), `"owner":"key:alice"`)
+}
+
+// TestRouter_RecreatedUser проверяет, что пользователь, созданный заново с именем удалённого,
+// не получает его ссылки.
+func TestRouter_RecreatedUser(t *testing.T) {
+	router := newTestRouter(t)
+
+	do := func(method, path, body string, auth func(r *http.Request)) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, httpserver.APIPrefix+path, strings.NewReader(body))
+		auth(req)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+	asAdmin := func(r *http.Request) { r.SetBasicAuth("admin", "secret-pass") }
+	asBob := func(r *http.Request) { r.SetBasicAuth("bob", "bob-pass-2") }
+
+	rr := do(http.MethodPost, "/admin/users", `{"username": "bob", "password": "bob-pass-1", "role": "editor"}`, asAdmin)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	rr = do(http.MethodPost, "/url", `{"url": "https://go.dev", "alias": "bob-link"}`, func(r *http.Request) {
+		r.SetBasicAuth("bob", "bob-pass-1")
+	})
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	rr = do(http.MethodDelete, "/admin/users/bob", "", asAdmin)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	rr = do(http.MethodPost, "/admin/users", `{"username": "bob", "password": "bob-pass-2", "role": "editor"}`, asAdmin)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	rr = do(http.MethodPatch, "/url/bob-link", `{"url": "https://example.com"}`, asBob)
+	require.Equal(t, http.StatusForbidden, rr.Code, rr.Body.String())
+
+	rr = do(http.MethodDelete, "/url/bob-link", "", asBob)
+	require.Equal(t, http.StatusForbidden, rr.Code, rr.Body.String())
+
+	// Ссылка продолжает работать, а управлять ею может администратор
+	rr = do(http.MethodGet, "/url/bob-link", "", asAdmin)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.NotContains(t, rr.Body.String(), `"owner"`)
+
+	rr = do(http.MethodDelete, "/url/bob-link", "", asAdmin)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+}
+
+func TestRouter_ReservedAliases(t *testing.T) {
+	router := newTestRouter(t)
+
+	for _, a := range []string{"api", "url", "healthz", "readyz", "metrics", "Metrics"} {
+		body := `{"url": "https://go.dev", "alias": "` + a + `"}`
+		req := httptest.NewRequest(http.MethodPost, httpserver.APIPrefix+"/url", strings.NewReader(body))
+		req.SetBasicAuth("admin", "secret-pass")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusBadRequest, rr.Code, a)
+
+		var resp struct {
+			Code    string `json:"code"`
+			Details []struct {
+				Field string `json:"field"`
+				Rule  string `json:"rule"`
+			} `json:"details"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		assert.Equal(t, "validation_error", resp.Code)
+		require.Len(t, resp.Details, 1)
+		assert.Equal(t, "alias_reserved", resp.Details[0].Rule, a)
+	}
+}
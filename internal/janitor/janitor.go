@@ -0,0 +1,62 @@
+package janitor
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"URLite/internal/lib/logger/sl"
+)
+
+// ExpiredDeleter — интерфейс хранилища, умеющего удалять ссылки с истёкшим сроком действия.
+type ExpiredDeleter interface {
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+// Janitor периодически удаляет из хранилища ссылки с истёкшим сроком действия.
+type Janitor struct {
+	log      *slog.Logger
+	deleter  ExpiredDeleter
+	interval time.Duration
+}
+
+func New(log *slog.Logger, deleter ExpiredDeleter, interval time.Duration) *Janitor {
+	return &Janitor{
+		log:      log.With(slog.String("component", "janitor")),
+		deleter:  deleter,
+		interval: interval,
+	}
+}
+
+// Run удаляет просроченные ссылки каждые interval, пока не будет отменён ctx.
+// Нулевой или отрицательный interval отключает удаление: Run сразу возвращается.
+func (j *Janitor) Run(ctx context.Context) {
+	if j.interval <= 0 {
+		j.log.Info("janitor disabled")
+		return
+	}
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			j.purge(now)
+		}
+	}
+}
+
+func (j *Janitor) purge(now time.Time) {
+	deleted, err := j.deleter.DeleteExpired(now)
+	if err != nil {
+		j.log.Error("failed to delete expired urls", sl.Err(err))
+		return
+	}
+
+	if deleted > 0 {
+		j.log.Info("expired urls deleted", slog.Int64("count", deleted))
+	}
+}
@@ -0,0 +1,72 @@
+package janitor_test
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"URLite/internal/janitor"
+	"URLite/internal/lib/logger/handlers/slogdiscard"
+	"github.com/stretchr/testify/require"
+)
+
+type expiredDeleterStub struct {
+	calls atomic.Int64
+}
+
+func (s *expiredDeleterStub) DeleteExpired(time.Time) (int64, error) {
+	s.calls.Add(1)
+	return 1, nil
+}
+
+func TestJanitor_Run(t *testing.T) {
+	deleter := &expiredDeleterStub{}
+	j := janitor.New(slogdiscard.NewDiscardLogger(), deleter, 5*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		j.Run(ctx)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool {
+		return deleter.calls.Load() >= 2
+	}, time.Second, time.Millisecond)
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("janitor did not stop after context cancellation")
+	}
+}
+
+func TestJanitor_RunDisabled(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		interval := interval
+
+		t.Run(fmt.Sprint(interval), func(t *testing.T) {
+			deleter := &expiredDeleterStub{}
+			j := janitor.New(slogdiscard.NewDiscardLogger(), deleter, interval)
+
+			done := make(chan struct{})
+			go func() {
+				j.Run(context.Background())
+				close(done)
+			}()
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("disabled janitor did not return")
+			}
+
+			require.Zero(t, deleter.calls.Load())
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package alias
+
+import (
+	"sync"
+
+	"URLite/internal/lib/random"
+)
+
+// Стратегии генерации псевдонимов.
+const (
+	// StrategyRandom — случайные строки из латинских букв и цифр.
+	StrategyRandom = "random"
+	// StrategyBase62 — идентификатор ссылки в base62, самые короткие псевдонимы.
+	StrategyBase62 = "base62"
+	// StrategySqids — обратимо перемешанный идентификатор ссылки.
+	StrategySqids = "sqids"
+	// StrategyWords — читаемые псевдонимы вида "adjective-noun-42".
+	StrategyWords = "words"
+)
+
+const (
+	// DefaultLength — начальная длина случайных псевдонимов.
+	DefaultLength = 6
+	// DefaultMaxLength ограничивает рост длины случайных псевдонимов.
+	DefaultMaxLength = 16
+
+	// window — сколько псевдонимов генерируется между пересчётами доли коллизий.
+	window = 100
+	// maxCollisionRate — доля коллизий в окне, после которой длина псевдонимов растёт.
+	maxCollisionRate = 0.1
+
+	// placeholderLength — длина временного псевдонима, под которым ссылка сохраняется,
+	// пока псевдоним из её идентификатора ещё не известен.
+	placeholderLength = DefaultMaxLength
+)
+
+// adaptiveLength — длина псевдонимов, которая увеличивается на единицу,
+// если сгенерированные псевдонимы слишком часто оказываются заняты, но не больше max.
+type adaptiveLength struct {
+	max int
+
+	mu         sync.Mutex
+	current    int
+	generated  int
+	collisions int
+}
+
+func newAdaptiveLength(length, max int) *adaptiveLength {
+	if max < length {
+		max = length
+	}
+
+	return &adaptiveLength{current: length, max: max}
+}
+
+// next учитывает новый псевдоним и возвращает длину, которую он должен получить.
+func (l *adaptiveLength) next() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	length := l.current
+	l.generated++
+	l.adjust()
+
+	return length
+}
+
+// collision учитывает занятый псевдоним.
+func (l *adaptiveLength) collision() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.collisions++
+	l.adjust()
+}
+
+func (l *adaptiveLength) get() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.current
+}
+
+// adjust увеличивает длину, если в окне набралось слишком много коллизий.
+// Вызывается под l.mu.
+func (l *adaptiveLength) adjust() {
+	if l.collisions > maxCollisionRate*window && l.current < l.max {
+		l.current++
+		l.generated, l.collisions = 0, 0
+		return
+	}
+
+	if l.generated >= window {
+		l.generated, l.collisions = 0, 0
+	}
+}
+
+// Random генерирует случайные псевдонимы из латинских букв и цифр.
+// Если сгенерированные псевдонимы слишком часто оказываются заняты,
+// длина новых псевдонимов увеличивается на один символ, но не больше MaxLength.
+type Random struct {
+	length *adaptiveLength
+}
+
+// NewRandom создаёт Random с начальной длиной length и максимальной длиной maxLength.
+// Нулевые значения заменяются на DefaultLength и DefaultMaxLength.
+func NewRandom(length, maxLength int) *Random {
+	if length <= 0 {
+		length = DefaultLength
+	}
+	if maxLength <= 0 {
+		maxLength = DefaultMaxLength
+	}
+
+	return &Random{length: newAdaptiveLength(length, maxLength)}
+}
+
+// Generate возвращает новый случайный псевдоним.
+func (g *Random) Generate() (string, error) {
+	return random.String(g.length.next())
+}
+
+// ReportCollision сообщает, что сгенерированный псевдоним уже занят.
+func (g *Random) ReportCollision() {
+	g.length.collision()
+}
+
+// Length возвращает текущую длину генерируемых псевдонимов.
+func (g *Random) Length() int {
+	return g.length.get()
+}
+
+// placeholder возвращает временный псевдоним для генераторов, которые строят псевдоним
+// из идентификатора ссылки. Коллизии временных псевдонимов обрабатываются как обычно,
+// а после сохранения ссылки псевдоним заменяется на постоянный.
+func placeholder() (string, error) {
+	return random.String(placeholderLength)
+}
@@ -0,0 +1,189 @@
+package alias_test
+
+import (
+	"testing"
+
+	"URLite/internal/lib/alias"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRandom_Generate(t *testing.T) {
+	g := alias.NewRandom(0, 0)
+
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		a, err := g.Generate()
+		require.NoError(t, err)
+		require.Len(t, a, alias.DefaultLength)
+		require.Regexp(t, "^[a-zA-Z0-9]+$", a)
+		require.False(t, seen[a], "duplicate alias %q", a)
+		seen[a] = true
+	}
+
+	// Без коллизий длина не меняется
+	assert.Equal(t, alias.DefaultLength, g.Length())
+}
+
+func TestRandom_GrowsOnCollisions(t *testing.T) {
+	g := alias.NewRandom(4, 6)
+
+	// Редкие коллизии не увеличивают длину
+	for i := 0; i < 300; i++ {
+		_, err := g.Generate()
+		require.NoError(t, err)
+		if i%50 == 0 {
+			g.ReportCollision()
+		}
+	}
+	require.Equal(t, 4, g.Length())
+
+	// Каждый второй псевдоним занят — длина растёт
+	for i := 0; i < 30; i++ {
+		_, err := g.Generate()
+		require.NoError(t, err)
+		if i%2 == 0 {
+			g.ReportCollision()
+		}
+	}
+	require.Equal(t, 5, g.Length())
+
+	a, err := g.Generate()
+	require.NoError(t, err)
+	assert.Len(t, a, 5)
+
+	// Длина не превышает максимальную
+	for i := 0; i < 1000; i++ {
+		_, err := g.Generate()
+		require.NoError(t, err)
+		g.ReportCollision()
+	}
+	assert.Equal(t, 6, g.Length())
+}
+
+func TestBase62_FromID(t *testing.T) {
+	var g alias.Base62
+
+	cases := []struct {
+		id    int64
+		alias string
+	}{
+		{id: 0, alias: "0"},
+		{id: 1, alias: "1"},
+		{id: 61, alias: "Z"},
+		{id: 62, alias: "10"},
+		{id: 3843, alias: "ZZ"},
+		{id: 1<<63 - 1, alias: "aZl8N0y58M7"},
+	}
+
+	for _, tc := range cases {
+		a, err := g.FromID(tc.id)
+		require.NoError(t, err)
+		assert.Equal(t, tc.alias, a, "id %d", tc.id)
+
+		id, err := g.Decode(a)
+		require.NoError(t, err)
+		assert.Equal(t, tc.id, id)
+	}
+
+	_, err := g.FromID(-1)
+	assert.Error(t, err)
+
+	_, err = g.Decode("a-b")
+	assert.Error(t, err)
+}
+
+func TestSqids_RoundTrip(t *testing.T) {
+	g, err := alias.NewSqids("", 0, nil)
+	require.NoError(t, err)
+
+	seen := make(map[string]bool)
+	for id := int64(0); id < 5000; id++ {
+		a, err := g.FromID(id)
+		require.NoError(t, err)
+		require.Regexp(t, "^[a-zA-Z0-9]+$", a)
+		require.False(t, seen[a], "duplicate alias %q", a)
+		seen[a] = true
+
+		got, err := g.Decode(a)
+		require.NoError(t, err)
+		require.Equal(t, id, got)
+	}
+
+	// Соседние идентификаторы не дают похожих псевдонимов
+	a1, _ := g.FromID(1)
+	a2, _ := g.FromID(2)
+	assert.NotEqual(t, a1[:1], a2[:1])
+
+	_, err = g.Decode("not-an-alias")
+	assert.Error(t, err)
+}
+
+func TestSqids_Options(t *testing.T) {
+	g, err := alias.NewSqids("0123456789abcdef", 8, nil)
+	require.NoError(t, err)
+
+	a, err := g.FromID(7)
+	require.NoError(t, err)
+	assert.Len(t, a, 8)
+	assert.Regexp(t, "^[0-9a-f]+$", a)
+
+	id, err := g.Decode(a)
+	require.NoError(t, err)
+	assert.EqualValues(t, 7, id)
+
+	// Другой алфавит — другие псевдонимы
+	other, err := alias.NewSqids("fedcba9876543210", 8, nil)
+	require.NoError(t, err)
+	b, err := other.FromID(7)
+	require.NoError(t, err)
+	assert.NotEqual(t, a, b)
+
+	_, err = alias.NewSqids("ab", 0, nil)
+	assert.Error(t, err, "too short alphabet")
+	_, err = alias.NewSqids("abca", 0, nil)
+	assert.Error(t, err, "repeated characters")
+	_, err = alias.NewSqids("", alias.DefaultMaxLength, nil)
+	assert.Error(t, err, "min length leaves no room for placeholders")
+}
+
+func TestSqids_Blocklist(t *testing.T) {
+	plain, err := alias.NewSqids("", 0, nil)
+	require.NoError(t, err)
+
+	blocked, err := plain.FromID(100)
+	require.NoError(t, err)
+
+	g, err := alias.NewSqids("", 0, []string{blocked})
+	require.NoError(t, err)
+
+	a, err := g.FromID(100)
+	require.NoError(t, err)
+	assert.NotEqual(t, blocked, a)
+
+	id, err := g.Decode(a)
+	require.NoError(t, err)
+	assert.EqualValues(t, 100, id)
+}
+
+func TestWords_Generate(t *testing.T) {
+	g := alias.NewWords()
+
+	for i := 0; i < 100; i++ {
+		a, err := g.Generate()
+		require.NoError(t, err)
+		require.Regexp(t, "^[a-z]+-[a-z]+-[0-9]{2}$", a)
+	}
+
+	// Частые коллизии удлиняют число в конце
+	for i := 0; i < 15; i++ {
+		_, err := g.Generate()
+		require.NoError(t, err)
+		g.ReportCollision()
+	}
+	require.Equal(t, 3, g.Digits())
+
+	a, err := g.Generate()
+	require.NoError(t, err)
+	assert.Regexp(t, "^[a-z]+-[a-z]+-[0-9]{3}$", a)
+}
@@ -0,0 +1,69 @@
+package alias
+
+import (
+	"errors"
+	"strings"
+)
+
+const base62Alphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// Base62 строит псевдоним из идентификатора сохранённой ссылки в base62.
+// Это самые короткие псевдонимы, но по ним легко перебрать все ссылки сервиса.
+type Base62 struct{}
+
+// Generate возвращает временный псевдоним, под которым ссылка сохраняется до получения идентификатора.
+func (Base62) Generate() (string, error) {
+	return placeholder()
+}
+
+// ReportCollision ничего не делает: псевдонимы из разных идентификаторов не совпадают.
+func (Base62) ReportCollision() {}
+
+// FromID возвращает псевдоним ссылки с идентификатором id.
+func (Base62) FromID(id int64) (string, error) {
+	if id < 0 {
+		return "", errors.New("alias.Base62: negative id")
+	}
+
+	return encodeBase(uint64(id), base62Alphabet), nil
+}
+
+// Decode возвращает идентификатор ссылки по её псевдониму.
+func (Base62) Decode(alias string) (int64, error) {
+	if alias == "" {
+		return 0, errors.New("alias.Base62: empty alias")
+	}
+
+	var id int64
+	for _, c := range alias {
+		i := strings.IndexRune(base62Alphabet, c)
+		if i < 0 {
+			return 0, errors.New("alias.Base62: invalid character")
+		}
+
+		if id > (1<<63-1-int64(i))/62 {
+			return 0, errors.New("alias.Base62: id overflows int64")
+		}
+		id = id*62 + int64(i)
+	}
+
+	return id, nil
+}
+
+// encodeBase записывает n в системе счисления с цифрами из alphabet.
+func encodeBase(n uint64, alphabet string) string {
+	base := uint64(len(alphabet))
+
+	var buf [64]byte
+	i := len(buf)
+	for {
+		i--
+		buf[i] = alphabet[n%base]
+		n /= base
+		if n == 0 {
+			break
+		}
+	}
+
+	return string(buf[i:])
+}
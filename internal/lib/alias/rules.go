@@ -0,0 +1,118 @@
+package alias
+
+import (
+	"fmt"
+	"strings"
+
+	"URLite/internal/lib/validate"
+)
+
+const (
+	// DefaultCustomMinLength — минимальная длина псевдонима, заданного клиентом.
+	DefaultCustomMinLength = 1
+	// DefaultCustomMaxLength — максимальная длина псевдонима, заданного клиентом.
+	DefaultCustomMaxLength = 64
+
+	// Tag — тег validate, проверяющий псевдоним по правилам Rules.
+	Tag = "alias"
+)
+
+// RulesOptions — параметры правил для псевдонимов.
+type RulesOptions struct {
+	MinLength int
+	MaxLength int
+	// Lowercase запрещает заглавные буквы в псевдонимах.
+	Lowercase bool
+	// Reserved — псевдонимы, которые нельзя занять, например пути маршрутов сервиса.
+	Reserved []string
+	// Blocklist — слова, которых не должно быть в псевдонимах.
+	Blocklist []string
+}
+
+// Rules проверяет псевдонимы, которые задают клиенты: длину, допустимые символы, регистр,
+// зарезервированные слова и слова из списка запрещённых. Зарезервированные и запрещённые
+// слова сравниваются без учёта регистра.
+type Rules struct {
+	opts      RulesOptions
+	reserved  map[string]bool
+	blocklist []string
+}
+
+// NewRules создаёт Rules. Нулевые длины заменяются на DefaultCustomMinLength и DefaultCustomMaxLength.
+func NewRules(opts RulesOptions) *Rules {
+	if opts.MinLength <= 0 {
+		opts.MinLength = DefaultCustomMinLength
+	}
+	if opts.MaxLength <= 0 {
+		opts.MaxLength = DefaultCustomMaxLength
+	}
+	if opts.MaxLength < opts.MinLength {
+		opts.MaxLength = opts.MinLength
+	}
+
+	r := &Rules{opts: opts, reserved: make(map[string]bool)}
+	r.Reserve(opts.Reserved...)
+
+	for _, w := range opts.Blocklist {
+		if w = strings.ToLower(strings.TrimSpace(w)); w != "" {
+			r.blocklist = append(r.blocklist, w)
+		}
+	}
+
+	return r
+}
+
+// Reserve добавляет зарезервированные псевдонимы. Вызывается до начала проверок.
+func (r *Rules) Reserve(words ...string) {
+	for _, w := range words {
+		r.reserved[strings.ToLower(w)] = true
+	}
+}
+
+// Register добавляет в v тег Tag и правила, из которых он состоит.
+func (r *Rules) Register(v *validate.Validator) {
+	v.RegisterString("alias_charset", validCharset)
+	v.RegisterString("alias_case", func(s string) bool { return s == strings.ToLower(s) })
+	v.RegisterString("alias_reserved", func(s string) bool { return !r.reserved[strings.ToLower(s)] })
+	v.RegisterString("alias_blocked", func(s string) bool { return !r.blocked(s) })
+
+	tags := fmt.Sprintf("min=%d,max=%d,alias_charset", r.opts.MinLength, r.opts.MaxLength)
+	if r.opts.Lowercase {
+		tags += ",alias_case"
+	}
+	tags += ",alias_reserved,alias_blocked"
+
+	v.RegisterAlias(Tag, tags)
+}
+
+// Allowed сообщает, можно ли выдать псевдоним: он не зарезервирован и не содержит запрещённых слов.
+// Используется для сгенерированных псевдонимов, длину и символы которых определяет генератор.
+func (r *Rules) Allowed(alias string) bool {
+	return !r.reserved[strings.ToLower(alias)] && !r.blocked(alias)
+}
+
+func (r *Rules) blocked(alias string) bool {
+	alias = strings.ToLower(alias)
+
+	for _, w := range r.blocklist {
+		if strings.Contains(alias, w) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validCharset сообщает, состоит ли псевдоним только из латинских букв, цифр, '-' и '_',
+// которые не нужно экранировать в пути URL.
+func validCharset(s string) bool {
+	for _, c := range s {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,74 @@
+package alias_test
+
+import (
+	"strings"
+	"testing"
+
+	"URLite/internal/lib/alias"
+	"URLite/internal/lib/validate"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type request struct {
+	Alias string `json:"alias" validate:"omitempty,alias"`
+}
+
+func TestRules_Register(t *testing.T) {
+	rules := alias.NewRules(alias.RulesOptions{
+		MinLength: 3,
+		MaxLength: 10,
+		Lowercase: true,
+		Reserved:  []string{"Login"},
+		Blocklist: []string{"spam"},
+	})
+	rules.Reserve("metrics")
+
+	v := validate.New()
+	rules.Register(v)
+
+	cases := []struct {
+		alias string
+		rule  string // Нарушенное правило, пустое — псевдоним допустим
+	}{
+		{alias: "go-dev_1"},
+		{alias: ""},
+		{alias: "go", rule: "min"},
+		{alias: strings.Repeat("a", 11), rule: "max"},
+		{alias: "a b c", rule: "alias_charset"},
+		{alias: "a/b/c", rule: "alias_charset"},
+		{alias: "привет", rule: "alias_charset"},
+		{alias: "GoDev", rule: "alias_case"},
+		{alias: "login", rule: "alias_reserved"},
+		{alias: "metrics", rule: "alias_reserved"},
+		{alias: "nospamhere", rule: "alias_blocked"},
+	}
+
+	for _, tc := range cases {
+		err := v.Struct(request{Alias: tc.alias})
+		if tc.rule == "" {
+			assert.NoError(t, err, tc.alias)
+			continue
+		}
+
+		var errs validator.ValidationErrors
+		require.ErrorAs(t, err, &errs, tc.alias)
+		require.Len(t, errs, 1)
+		assert.Equal(t, tc.rule, errs[0].ActualTag(), tc.alias)
+		assert.Equal(t, "alias", errs[0].Field())
+	}
+}
+
+func TestRules_Allowed(t *testing.T) {
+	rules := alias.NewRules(alias.RulesOptions{Reserved: []string{"api"}, Blocklist: []string{"Spam"}})
+
+	assert.True(t, rules.Allowed("aZ3kQ9"))
+	assert.False(t, rules.Allowed("API"))
+	assert.False(t, rules.Allowed("xSPAMx"))
+
+	// Регистр разрешён, пока не включён Lowercase
+	v := validate.New()
+	rules.Register(v)
+	assert.NoError(t, v.Struct(request{Alias: "GoDev"}))
+}
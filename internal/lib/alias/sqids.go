@@ -0,0 +1,230 @@
+package alias
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DefaultSqidsAlphabet — алфавит Sqids по умолчанию.
+const DefaultSqidsAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// Sqids строит псевдоним из идентификатора сохранённой ссылки по алгоритму Sqids
+// (https://sqids.org): псевдонимы не выдают порядок создания ссылок, но однозначно
+// декодируются обратно в идентификатор. Порядок символов алфавита задаёт перестановку,
+// поэтому разные алфавиты дают разные псевдонимы для одного идентификатора.
+type Sqids struct {
+	alphabet  []byte
+	minLength int
+	blocklist []string
+}
+
+// NewSqids создаёт Sqids. Пустой alphabet заменяется на DefaultSqidsAlphabet.
+// Псевдонимы короче minLength дополняются до этой длины, а псевдонимы,
+// содержащие слова из blocklist, кодируются заново.
+func NewSqids(alphabet string, minLength int, blocklist []string) (*Sqids, error) {
+	const op = "alias.NewSqids"
+
+	if alphabet == "" {
+		alphabet = DefaultSqidsAlphabet
+	}
+
+	if len(alphabet) < 3 {
+		return nil, fmt.Errorf("%s: alphabet must contain at least 3 characters", op)
+	}
+
+	seen := make(map[rune]bool, len(alphabet))
+	for _, c := range alphabet {
+		if c > 0x7f {
+			return nil, fmt.Errorf("%s: alphabet must contain only ascii characters", op)
+		}
+		if seen[c] {
+			return nil, fmt.Errorf("%s: alphabet must contain unique characters", op)
+		}
+		seen[c] = true
+	}
+
+	if minLength < 0 || minLength >= placeholderLength {
+		return nil, fmt.Errorf("%s: min length must be between 0 and %d", op, placeholderLength-1)
+	}
+
+	// Слово из символов не из алфавита никогда не встретится в псевдониме
+	lowerAlphabet := strings.ToLower(alphabet)
+	var words []string
+	for _, w := range blocklist {
+		w = strings.ToLower(w)
+		if len(w) < 3 {
+			continue
+		}
+
+		inAlphabet := true
+		for _, c := range w {
+			if !strings.ContainsRune(lowerAlphabet, c) {
+				inAlphabet = false
+				break
+			}
+		}
+		if inAlphabet {
+			words = append(words, w)
+		}
+	}
+
+	return &Sqids{
+		alphabet:  shuffle([]byte(alphabet)),
+		minLength: minLength,
+		blocklist: words,
+	}, nil
+}
+
+// Generate возвращает временный псевдоним, под которым ссылка сохраняется до получения идентификатора.
+func (s *Sqids) Generate() (string, error) {
+	return placeholder()
+}
+
+// ReportCollision ничего не делает: псевдонимы из разных идентификаторов не совпадают.
+func (s *Sqids) ReportCollision() {}
+
+// FromID возвращает псевдоним ссылки с идентификатором id.
+func (s *Sqids) FromID(id int64) (string, error) {
+	if id < 0 {
+		return "", errors.New("alias.Sqids: negative id")
+	}
+
+	for increment := 0; increment <= len(s.alphabet); increment++ {
+		alias := s.encode(uint64(id), increment)
+		if !s.blocked(alias) {
+			return alias, nil
+		}
+	}
+
+	return "", errors.New("alias.Sqids: every encoding of the id is blocked")
+}
+
+// Decode возвращает идентификатор ссылки по её псевдониму.
+// Псевдонимы, которые FromID не мог вернуть, считаются ошибочными.
+func (s *Sqids) Decode(alias string) (int64, error) {
+	const op = "alias.Sqids.Decode"
+
+	if alias == "" {
+		return 0, fmt.Errorf("%s: empty alias", op)
+	}
+
+	offset := strings.IndexByte(string(s.alphabet), alias[0])
+	if offset < 0 {
+		return 0, fmt.Errorf("%s: invalid character", op)
+	}
+
+	alphabet := rotate(s.alphabet, offset)
+	reverse(alphabet)
+
+	// Псевдоним одного идентификатора — префикс и число до разделителя, дальше дополнение
+	chunk, _, _ := strings.Cut(alias[1:], string(alphabet[0]))
+
+	id, ok := decodeBase(chunk, alphabet[1:])
+	if !ok {
+		return 0, fmt.Errorf("%s: invalid alias", op)
+	}
+
+	if canonical, err := s.FromID(id); err != nil || canonical != alias {
+		return 0, fmt.Errorf("%s: invalid alias", op)
+	}
+
+	return id, nil
+}
+
+func (s *Sqids) encode(id uint64, increment int) string {
+	alphabet := make([]byte, len(s.alphabet))
+	copy(alphabet, s.alphabet)
+
+	offset := (1 + int(alphabet[id%uint64(len(alphabet))]) + increment) % len(alphabet)
+	alphabet = rotate(alphabet, offset)
+
+	prefix := alphabet[0]
+	reverse(alphabet)
+
+	var b strings.Builder
+	b.WriteByte(prefix)
+	b.WriteString(encodeBase(id, string(alphabet[1:])))
+
+	if b.Len() < s.minLength {
+		b.WriteByte(alphabet[0])
+
+		for b.Len() < s.minLength {
+			alphabet = shuffle(alphabet)
+			b.Write(alphabet[:min(s.minLength-b.Len(), len(alphabet))])
+		}
+	}
+
+	return b.String()
+}
+
+// blocked сообщает, содержит ли псевдоним слово из списка запрещённых.
+// Короткие слова запрещены только целиком, слова с цифрами — в начале и в конце псевдонима.
+func (s *Sqids) blocked(alias string) bool {
+	alias = strings.ToLower(alias)
+
+	for _, w := range s.blocklist {
+		if len(w) > len(alias) {
+			continue
+		}
+
+		switch {
+		case len(alias) <= 3 || len(w) <= 3:
+			if alias == w {
+				return true
+			}
+		case strings.ContainsAny(w, "0123456789"):
+			if strings.HasPrefix(alias, w) || strings.HasSuffix(alias, w) {
+				return true
+			}
+		case strings.Contains(alias, w):
+			return true
+		}
+	}
+
+	return false
+}
+
+// shuffle возвращает детерминированную перестановку alphabet.
+func shuffle(alphabet []byte) []byte {
+	result := make([]byte, len(alphabet))
+	copy(result, alphabet)
+
+	n := len(result)
+	for i, j := 0, n-1; j > 0; i, j = i+1, j-1 {
+		r := (i*j + int(result[i]) + int(result[j])) % n
+		result[i], result[r] = result[r], result[i]
+	}
+
+	return result
+}
+
+func rotate(alphabet []byte, offset int) []byte {
+	return append(append([]byte{}, alphabet[offset:]...), alphabet[:offset]...)
+}
+
+func reverse(alphabet []byte) {
+	for i, j := 0, len(alphabet)-1; i < j; i, j = i+1, j-1 {
+		alphabet[i], alphabet[j] = alphabet[j], alphabet[i]
+	}
+}
+
+// decodeBase — обратная к encodeBase функция.
+func decodeBase(s string, alphabet []byte) (int64, bool) {
+	if s == "" {
+		return 0, false
+	}
+
+	base := int64(len(alphabet))
+
+	var n int64
+	for i := 0; i < len(s); i++ {
+		d := strings.IndexByte(string(alphabet), s[i])
+		if d < 0 || n > (1<<63-1-int64(d))/base {
+			return 0, false
+		}
+		n = n*base + int64(d)
+	}
+
+	return n, true
+}
@@ -0,0 +1,83 @@
+package alias
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"URLite/internal/lib/random"
+)
+
+const (
+	// wordsDigits — начальное число цифр в конце читаемых псевдонимов.
+	wordsDigits = 2
+	// wordsMaxDigits ограничивает рост числа цифр при частых коллизиях.
+	wordsMaxDigits = 6
+)
+
+var (
+	//go:embed words/adjectives.txt
+	adjectivesFile string
+	//go:embed words/nouns.txt
+	nounsFile string
+
+	adjectives = strings.Fields(adjectivesFile)
+	nouns      = strings.Fields(nounsFile)
+)
+
+// Words генерирует читаемые псевдонимы вида "adjective-noun-42" из встроенных списков слов.
+// Если псевдонимы слишком часто оказываются заняты, число в конце становится на цифру длиннее.
+type Words struct {
+	digits *adaptiveLength
+}
+
+// NewWords создаёт Words с двузначным числом в конце псевдонимов.
+func NewWords() *Words {
+	return &Words{digits: newAdaptiveLength(wordsDigits, wordsMaxDigits)}
+}
+
+// Generate возвращает новый псевдоним из случайных прилагательного, существительного и числа.
+func (g *Words) Generate() (string, error) {
+	digits := g.digits.next()
+
+	adjective, err := pick(adjectives)
+	if err != nil {
+		return "", err
+	}
+
+	noun, err := pick(nouns)
+	if err != nil {
+		return "", err
+	}
+
+	limit := 1
+	for i := 0; i < digits; i++ {
+		limit *= 10
+	}
+
+	n, err := random.Int(limit)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s-%s-%0*d", adjective, noun, digits, n), nil
+}
+
+// ReportCollision сообщает, что сгенерированный псевдоним уже занят.
+func (g *Words) ReportCollision() {
+	g.digits.collision()
+}
+
+// Digits возвращает текущее число цифр в конце псевдонимов.
+func (g *Words) Digits() int {
+	return g.digits.get()
+}
+
+func pick(words []string) (string, error) {
+	i, err := random.Int(len(words))
+	if err != nil {
+		return "", err
+	}
+
+	return words[i], nil
+}
@@ -0,0 +1,95 @@
+package response
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+// Code — машиночитаемый код ошибки API.
+type Code string
+
+const (
+	CodeBadRequest      Code = "bad_request"
+	CodeValidation      Code = "validation_error"
+	CodeUnauthorized    Code = "unauthorized"
+	CodeForbidden       Code = "forbidden"
+	CodeNotFound        Code = "not_found"
+	CodeConflict        Code = "conflict"
+	CodeGone            Code = "gone"
+	CodeTooManyRequests Code = "too_many_requests"
+	CodeInternal        Code = "internal_error"
+	CodeUnavailable     Code = "unavailable"
+)
+
+var codeStatuses = map[Code]int{
+	CodeBadRequest:      http.StatusBadRequest,
+	CodeValidation:      http.StatusBadRequest,
+	CodeUnauthorized:    http.StatusUnauthorized,
+	CodeForbidden:       http.StatusForbidden,
+	CodeNotFound:        http.StatusNotFound,
+	CodeConflict:        http.StatusConflict,
+	CodeGone:            http.StatusGone,
+	CodeTooManyRequests: http.StatusTooManyRequests,
+	CodeInternal:        http.StatusInternalServerError,
+	CodeUnavailable:     http.StatusServiceUnavailable,
+}
+
+// HTTPStatus возвращает HTTP-статус ответа с ошибкой. Неизвестные коды считаются внутренней ошибкой.
+func (c Code) HTTPStatus() int {
+	if status, ok := codeStatuses[c]; ok {
+		return status
+	}
+
+	return http.StatusInternalServerError
+}
+
+// APIError — ошибка, которую обработчик возвращает клиенту.
+type APIError struct {
+	Code    Code
+	Message string
+	Details []FieldError
+}
+
+// NewError создаёт ошибку API с кодом code и сообщением msg для клиента.
+func NewError(code Code, msg string) *APIError {
+	return &APIError{Code: code, Message: msg}
+}
+
+// NewValidationError создаёт ошибку API из ошибок проверки тела запроса.
+func NewValidationError(errs validator.ValidationErrors) *APIError {
+	details := fieldErrors(errs)
+
+	msgs := make([]string, 0, len(details))
+	for _, d := range details {
+		msgs = append(msgs, d.Message)
+	}
+
+	return &APIError{
+		Code:    CodeValidation,
+		Message: strings.Join(msgs, ", "),
+		Details: details,
+	}
+}
+
+func (e *APIError) Error() string {
+	return string(e.Code) + ": " + e.Message
+}
+
+// Response возвращает тело ответа с ошибкой.
+func (e *APIError) Response() Response {
+	return Response{
+		Status:  StatusError,
+		Error:   e.Message,
+		Code:    e.Code,
+		Details: e.Details,
+	}
+}
+
+// RenderError отправляет клиенту ошибку err с HTTP-статусом, соответствующим её коду.
+func RenderError(w http.ResponseWriter, r *http.Request, err *APIError) {
+	render.Status(r, err.Code.HTTPStatus())
+	render.JSON(w, r, err.Response())
+}
@@ -0,0 +1,78 @@
+package response_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"URLite/internal/lib/api/response"
+	"URLite/internal/lib/validate"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCode_HTTPStatus(t *testing.T) {
+	cases := []struct {
+		code   response.Code
+		status int
+	}{
+		{code: response.CodeBadRequest, status: http.StatusBadRequest},
+		{code: response.CodeValidation, status: http.StatusBadRequest},
+		{code: response.CodeUnauthorized, status: http.StatusUnauthorized},
+		{code: response.CodeForbidden, status: http.StatusForbidden},
+		{code: response.CodeNotFound, status: http.StatusNotFound},
+		{code: response.CodeConflict, status: http.StatusConflict},
+		{code: response.CodeGone, status: http.StatusGone},
+		{code: response.CodeTooManyRequests, status: http.StatusTooManyRequests},
+		{code: response.CodeInternal, status: http.StatusInternalServerError},
+		{code: "unknown", status: http.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.status, tc.code.HTTPStatus(), tc.code)
+	}
+}
+
+func TestRenderError(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rr := httptest.NewRecorder()
+
+	response.RenderError(rr, req, response.NewError(response.CodeConflict, "url already exists"))
+
+	require.Equal(t, http.StatusConflict, rr.Code)
+
+	var resp response.Response
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, response.StatusError, resp.Status)
+	assert.Equal(t, "url already exists", resp.Error)
+	assert.Equal(t, response.CodeConflict, resp.Code)
+	assert.Empty(t, resp.Details)
+}
+
+func TestNewValidationError(t *testing.T) {
+	type request struct {
+		URL  string `json:"url" validate:"required,url"`
+		Role string `json:"role,omitempty" validate:"omitempty,oneof=admin editor"`
+	}
+
+	err := validate.Struct(request{URL: "not a url", Role: "root"})
+
+	var validateErr validator.ValidationErrors
+	require.ErrorAs(t, err, &validateErr)
+
+	apiErr := response.NewValidationError(validateErr)
+
+	assert.Equal(t, response.CodeValidation, apiErr.Code)
+	assert.Equal(t, "field URL is not a valid URL, field Role must be one of: admin editor", apiErr.Message)
+	assert.Equal(t, []response.FieldError{
+		{Field: "url", Rule: "url", Message: "field URL is not a valid URL"},
+		{Field: "role", Rule: "oneof", Message: "field Role must be one of: admin editor"},
+	}, apiErr.Details)
+
+	// Старое поле error сохраняется для совместимости
+	resp := apiErr.Response()
+	assert.Equal(t, apiErr.Message, resp.Error)
+	assert.Equal(t, apiErr.Details, resp.Details)
+}
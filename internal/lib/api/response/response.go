@@ -2,6 +2,7 @@ package response
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
@@ -10,6 +11,19 @@ import (
 type Response struct {
 	Status string `json:"status"`
 	Error  string `json:"error,omitempty"`
+	// Code — машиночитаемый код ошибки, на который могут опираться клиенты вместо текста Error.
+	Code Code `json:"code,omitempty"`
+	// Details перечисляет поля запроса, не прошедшие проверку.
+	Details []FieldError `json:"details,omitempty"`
+}
+
+// FieldError — ошибка проверки одного поля запроса.
+type FieldError struct {
+	// Field — имя поля в JSON.
+	Field string `json:"field"`
+	// Rule — нарушенное правило, например "required" или "url".
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
 }
 
 const (
@@ -31,21 +45,56 @@ func Error(msg string) Response {
 }
 
 func ValidationError(errs validator.ValidationErrors) Response {
-	var errMsgs []string
+	return NewValidationError(errs).Response()
+}
+
+func fieldErrors(errs validator.ValidationErrors) []FieldError {
+	details := make([]FieldError, 0, len(errs))
 
 	for _, err := range errs {
-		switch err.ActualTag() {
-		case "required":
-			errMsgs = append(errMsgs, fmt.Sprintf("field %s is a required field", err.Field()))
-		case "url":
-			errMsgs = append(errMsgs, fmt.Sprintf("field %s is not a valid URL", err.Field()))
-		default:
-			errMsgs = append(errMsgs, fmt.Sprintf("field %s is not valid", err.Field()))
-		}
+		details = append(details, FieldError{
+			Field:   err.Field(),
+			Rule:    err.ActualTag(),
+			Message: validationMessage(err),
+		})
 	}
 
-	return Response{
-		Status: StatusError,
-		Error:  strings.Join(errMsgs, ", "),
+	return details
+}
+
+// validationMessage описывает ошибку проверки поля. В тексте используется имя поля в Go,
+// чтобы сообщения не зависели от настройки валидатора.
+func validationMessage(err validator.FieldError) string {
+	switch err.ActualTag() {
+	case "required":
+		return fmt.Sprintf("field %s is a required field", err.StructField())
+	case "url":
+		return fmt.Sprintf("field %s is not a valid URL", err.StructField())
+	case "oneof":
+		return fmt.Sprintf("field %s must be one of: %s", err.StructField(), err.Param())
+	case "min":
+		if err.Kind() != reflect.String {
+			return fmt.Sprintf("field %s must be at least %s", err.StructField(), err.Param())
+		}
+		return fmt.Sprintf("field %s must be at least %s characters long", err.StructField(), err.Param())
+	case "max":
+		if err.Kind() != reflect.String {
+			return fmt.Sprintf("field %s must be at most %s", err.StructField(), err.Param())
+		}
+		return fmt.Sprintf("field %s must be at most %s characters long", err.StructField(), err.Param())
+	case "alias_charset":
+		return fmt.Sprintf("field %s may contain only latin letters, digits, '-' and '_'", err.StructField())
+	case "alias_case":
+		return fmt.Sprintf("field %s must be lowercase", err.StructField())
+	case "alias_reserved":
+		return fmt.Sprintf("field %s is reserved", err.StructField())
+	case "alias_blocked":
+		return fmt.Sprintf("field %s contains a blocked word", err.StructField())
+	case "excludes":
+		return fmt.Sprintf("field %s must not contain %q", err.StructField(), err.Param())
+	case "excluded_with":
+		return fmt.Sprintf("field %s cannot be used together with %s", err.StructField(), strings.ReplaceAll(err.Param(), " ", " or "))
+	default:
+		return fmt.Sprintf("field %s is not valid", err.StructField())
 	}
 }
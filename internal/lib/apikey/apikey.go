@@ -0,0 +1,47 @@
+// Package apikey генерирует ключи доступа к API и вычисляет их хеши для хранения.
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+const (
+	// keyPrefix отличает ключи URLite от других секретов, например при поиске утечек.
+	keyPrefix = "ulk_"
+	// secretBytes — число случайных байт в ключе.
+	secretBytes = 24
+	// displayLength — длина начала ключа, которое показывается в списке ключей.
+	displayLength = len(keyPrefix) + 8
+)
+
+// Generate возвращает новый случайный ключ.
+func Generate() (string, error) {
+	const op = "lib.apikey.Generate"
+
+	b := make([]byte, secretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return keyPrefix + hex.EncodeToString(b), nil
+}
+
+// Hash возвращает хеш ключа, под которым ключ хранится в базе.
+// Ключи содержат достаточно случайности, поэтому медленный хеш с солью не нужен.
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// Prefix возвращает начало ключа, по которому его можно узнать, не раскрывая сам ключ.
+func Prefix(key string) string {
+	if len(key) <= displayLength {
+		return key
+	}
+
+	return key[:displayLength]
+}
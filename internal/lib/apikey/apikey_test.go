@@ -0,0 +1,32 @@
+package apikey
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	first, err := Generate()
+	require.NoError(t, err)
+
+	second, err := Generate()
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(first, keyPrefix))
+	assert.Len(t, first, len(keyPrefix)+2*secretBytes)
+	assert.NotEqual(t, first, second)
+}
+
+func TestHash(t *testing.T) {
+	assert.Equal(t, Hash("ulk_test"), Hash("ulk_test"))
+	assert.NotEqual(t, Hash("ulk_test"), Hash("ulk_other"))
+	assert.Len(t, Hash("ulk_test"), 64)
+}
+
+func TestPrefix(t *testing.T) {
+	assert.Equal(t, "ulk_01234567", Prefix("ulk_0123456789abcdef"))
+	assert.Equal(t, "ulk_", Prefix("ulk_"))
+}
@@ -0,0 +1,37 @@
+// Package password хеширует и проверяет пароли пользователей с помощью bcrypt.
+package password
+
+import (
+	"fmt"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// dummyHash сравнивается с паролем, когда пользователь не найден, чтобы время ответа
+// не выдавало, существует ли пользователь.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("urlite-dummy-password"), bcrypt.DefaultCost)
+
+// Hash возвращает bcrypt-хеш пароля.
+func Hash(password string) (string, error) {
+	const op = "lib.password.Hash"
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return string(hash), nil
+}
+
+// Compare сообщает, соответствует ли пароль хешу.
+func Compare(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// CompareDummy тратит на проверку пароля столько же времени, сколько Compare,
+// и всегда возвращает false. Используется, если пользователь не найден.
+func CompareDummy(password string) bool {
+	_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+
+	return false
+}
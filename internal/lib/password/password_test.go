@@ -0,0 +1,19 @@
+package password_test
+
+import (
+	"testing"
+
+	"URLite/internal/lib/password"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashAndCompare(t *testing.T) {
+	hash, err := password.Hash("correct horse")
+	require.NoError(t, err)
+	require.NotEqual(t, "correct horse", hash)
+
+	require.True(t, password.Compare(hash, "correct horse"))
+	require.False(t, password.Compare(hash, "battery staple"))
+	require.False(t, password.Compare("not a hash", "correct horse"))
+	require.False(t, password.CompareDummy("correct horse"))
+}
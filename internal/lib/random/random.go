@@ -1,17 +1,59 @@
 package random
 
 import (
-	"math/rand"
-	"time"
+	"crypto/rand"
+	"fmt"
+	"math/big"
 )
 
+const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// maxByte — граница, выше которой случайные байты отбрасываются,
+// чтобы все символы charset выпадали с равной вероятностью.
+const maxByte = 256 - 256%len(charset)
+
+// String возвращает строку из length случайных латинских букв и цифр.
+// Источник случайности — crypto/rand, поэтому одновременные вызовы не дают одинаковых строк.
+func String(length int) (string, error) {
+	result := make([]byte, 0, length)
+	buf := make([]byte, length+length/4+1)
+
+	for len(result) < length {
+		if _, err := rand.Read(buf); err != nil {
+			return "", fmt.Errorf("random.String: %w", err)
+		}
+
+		for _, b := range buf {
+			if int(b) >= maxByte {
+				continue
+			}
+
+			result = append(result, charset[int(b)%len(charset)])
+			if len(result) == length {
+				break
+			}
+		}
+	}
+
+	return string(result), nil
+}
+
+// NewRandomString — как String, но паникует, если системный источник случайности недоступен.
 func NewRandomString(length int) string {
-	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-	seededRand := rand.New(rand.NewSource(time.Now().UnixNano()))
-	randomString := make([]byte, length)
-	for i := range randomString {
-		randomString[i] = charset[seededRand.Intn(len(charset))]
+	s, err := String(length)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+// Int возвращает случайное число из [0, n). n должно быть больше нуля.
+func Int(n int) (int, error) {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, fmt.Errorf("random.Int: %w", err)
 	}
 
-	return string(randomString)
+	return int(v.Int64()), nil
 }
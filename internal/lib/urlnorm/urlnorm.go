@@ -0,0 +1,215 @@
+// Package urlnorm приводит адреса ссылок к каноническому виду, чтобы один и тот же
+// адрес, записанный по-разному, хранился одинаково.
+package urlnorm
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+)
+
+// DefaultTrackingParams — параметры запроса, которые удаляются при StripTracking,
+// если список не задан. Звёздочка в конце означает любой параметр с этим префиксом.
+var DefaultTrackingParams = []string{"utm_*", "fbclid", "gclid", "yclid"}
+
+// defaultPorts — порты, которые не указываются в каноническом адресе.
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// Options — необязательные шаги нормализации.
+type Options struct {
+	// SortQuery упорядочивает параметры запроса по имени.
+	SortQuery bool
+	// StripTracking удаляет параметры запроса из TrackingParams.
+	StripTracking bool
+	// TrackingParams — удаляемые параметры. Пустой список заменяется на DefaultTrackingParams.
+	TrackingParams []string
+}
+
+// Normalizer приводит адреса к каноническому виду: схема и хост в нижнем регистре,
+// интернационализированные домены в punycode, без порта по умолчанию, с разрешёнными
+// сегментами "." и ".." в пути и без пустого запроса. Кодирование пути и значений
+// параметров запроса сохраняется.
+type Normalizer struct {
+	opts Options
+}
+
+func New(opts Options) *Normalizer {
+	if len(opts.TrackingParams) == 0 {
+		opts.TrackingParams = DefaultTrackingParams
+	}
+
+	return &Normalizer{opts: opts}
+}
+
+// Normalize возвращает канонический вид абсолютного адреса raw.
+func (n *Normalizer) Normalize(raw string) (string, error) {
+	const op = "urlnorm.Normalize"
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if u.Scheme == "" || u.Opaque != "" {
+		return "", fmt.Errorf("%s: not an absolute url", op)
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+
+	u.Host, err = normalizeHost(u.Scheme, u.Hostname(), u.Port())
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	escapedPath := removeDotSegments(u.EscapedPath())
+	u.Path, err = url.PathUnescape(escapedPath)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	u.RawPath = escapedPath
+
+	u.RawQuery = n.normalizeQuery(u.RawQuery)
+	u.ForceQuery = false
+
+	return u.String(), nil
+}
+
+// normalizeHost переводит хост в нижний регистр и punycode и убирает порт по умолчанию для схемы.
+func normalizeHost(scheme, host, port string) (string, error) {
+	host = strings.ToLower(host)
+
+	if !isASCII(host) {
+		ascii, err := idna.ToASCII(host)
+		if err != nil {
+			return "", err
+		}
+		host = ascii
+	}
+
+	if port == defaultPorts[scheme] {
+		port = ""
+	}
+
+	// IPv6-адреса в URL записываются в квадратных скобках
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+
+	if port != "" {
+		return host + ":" + port, nil
+	}
+
+	return host, nil
+}
+
+// normalizeQuery удаляет отслеживающие параметры и сортирует параметры по имени, если это включено.
+// Пары "имя=значение" переносятся без перекодирования.
+func (n *Normalizer) normalizeQuery(rawQuery string) string {
+	if rawQuery == "" || (!n.opts.SortQuery && !n.opts.StripTracking) {
+		return rawQuery
+	}
+
+	pairs := strings.Split(rawQuery, "&")
+
+	kept := pairs[:0]
+	for _, pair := range pairs {
+		if pair == "" {
+			continue
+		}
+		if n.opts.StripTracking && n.tracking(paramName(pair)) {
+			continue
+		}
+		kept = append(kept, pair)
+	}
+
+	if n.opts.SortQuery {
+		sort.SliceStable(kept, func(i, j int) bool {
+			return paramName(kept[i]) < paramName(kept[j])
+		})
+	}
+
+	return strings.Join(kept, "&")
+}
+
+func (n *Normalizer) tracking(name string) bool {
+	name = strings.ToLower(name)
+
+	for _, p := range n.opts.TrackingParams {
+		p = strings.ToLower(p)
+		if prefix, ok := strings.CutSuffix(p, "*"); ok {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+			continue
+		}
+		if name == p {
+			return true
+		}
+	}
+
+	return false
+}
+
+// paramName возвращает раскодированное имя параметра из пары "имя=значение".
+func paramName(pair string) string {
+	name, _, _ := strings.Cut(pair, "=")
+	if unescaped, err := url.QueryUnescape(name); err == nil {
+		return unescaped
+	}
+
+	return name
+}
+
+// removeDotSegments разрешает сегменты "." и ".." в пути по RFC 3986, раздел 5.2.4.
+func removeDotSegments(p string) string {
+	if p == "" {
+		return p
+	}
+
+	var out []string
+	segments := strings.Split(p, "/")
+	for i, s := range segments {
+		last := i == len(segments)-1
+
+		switch s {
+		case ".":
+			// Путь, оканчивающийся на "." или "..", указывает на каталог
+			if last {
+				out = append(out, "")
+			}
+		case "..":
+			if len(out) > 1 {
+				out = out[:len(out)-1]
+			}
+			if last {
+				out = append(out, "")
+			}
+		default:
+			out = append(out, s)
+		}
+	}
+
+	res := strings.Join(out, "/")
+	if strings.HasPrefix(p, "/") && !strings.HasPrefix(res, "/") {
+		res = "/" + res
+	}
+
+	return res
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+
+	return true
+}
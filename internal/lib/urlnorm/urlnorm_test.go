@@ -0,0 +1,134 @@
+package urlnorm_test
+
+import (
+	"testing"
+
+	"URLite/internal/lib/urlnorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	cases := []struct {
+		name string
+		opts urlnorm.Options
+		raw  string
+		want string
+	}{
+		{
+			name: "Scheme, host, default port, dot segments and empty query",
+			raw:  "HTTP://Example.COM:80/a/../b?",
+			want: "http://example.com/b",
+		},
+		{
+			name: "Already canonical",
+			raw:  "http://example.com/b",
+			want: "http://example.com/b",
+		},
+		{
+			name: "Path case is preserved",
+			raw:  "https://Example.com/Docs/Index.HTML",
+			want: "https://example.com/Docs/Index.HTML",
+		},
+		{
+			name: "Default https port",
+			raw:  "https://example.com:443/",
+			want: "https://example.com/",
+		},
+		{
+			name: "Non-default port is kept",
+			raw:  "https://example.com:8443/",
+			want: "https://example.com:8443/",
+		},
+		{
+			name: "Port of another scheme is kept",
+			raw:  "http://example.com:443/",
+			want: "http://example.com:443/",
+		},
+		{
+			name: "IDN to punycode",
+			raw:  "https://Пример.РФ/путь",
+			want: "https://xn--e1afmkfd.xn--p1ai/%D0%BF%D1%83%D1%82%D1%8C",
+		},
+		{
+			name: "IPv6 host",
+			raw:  "http://[::1]:80/a",
+			want: "http://[::1]/a",
+		},
+		{
+			name: "Dot segments",
+			raw:  "http://example.com/a/./b/../../c/./d/..",
+			want: "http://example.com/c/",
+		},
+		{
+			name: "Dot segments above root",
+			raw:  "http://example.com/../../a",
+			want: "http://example.com/a",
+		},
+		{
+			name: "Encoding is preserved",
+			raw:  "http://example.com/a%2Fb/c%20d?q=a+b&r=%2F",
+			want: "http://example.com/a%2Fb/c%20d?q=a+b&r=%2F",
+		},
+		{
+			name: "Fragment and user info are kept",
+			raw:  "http://user@Example.com/a#Top",
+			want: "http://user@example.com/a#Top",
+		},
+		{
+			name: "Query is not reordered by default",
+			raw:  "http://example.com/?b=2&a=1&utm_source=x",
+			want: "http://example.com/?b=2&a=1&utm_source=x",
+		},
+		{
+			name: "Sorted query",
+			opts: urlnorm.Options{SortQuery: true},
+			raw:  "http://example.com/?b=2&a=1&a=0&c",
+			want: "http://example.com/?a=1&a=0&b=2&c",
+		},
+		{
+			name: "Tracking params stripped",
+			opts: urlnorm.Options{StripTracking: true},
+			raw:  "http://example.com/?id=7&UTM_Source=x&utm_medium=y&fbclid=z&&gclid=w",
+			want: "http://example.com/?id=7",
+		},
+		{
+			name: "Only tracking params",
+			opts: urlnorm.Options{StripTracking: true},
+			raw:  "http://example.com/a?utm_source=x",
+			want: "http://example.com/a",
+		},
+		{
+			name: "Custom tracking params",
+			opts: urlnorm.Options{StripTracking: true, TrackingParams: []string{"ref", "mc_*"}},
+			raw:  "http://example.com/?ref=a&mc_cid=b&utm_source=c",
+			want: "http://example.com/?utm_source=c",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := urlnorm.New(tc.opts).Normalize(tc.raw)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+
+			// Нормализация идемпотентна
+			again, err := urlnorm.New(tc.opts).Normalize(got)
+			require.NoError(t, err)
+			assert.Equal(t, got, again)
+		})
+	}
+}
+
+func TestNormalize_Invalid(t *testing.T) {
+	n := urlnorm.New(urlnorm.Options{})
+
+	for _, raw := range []string{"/relative/path", "mailto:user@example.com", "http://exa mple.com/", "http://%zz/"} {
+		_, err := n.Normalize(raw)
+		assert.Error(t, err, raw)
+	}
+}
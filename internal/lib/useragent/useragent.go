@@ -0,0 +1,60 @@
+package useragent
+
+import "strings"
+
+const (
+	FamilyUnknown = "Unknown"
+	FamilyOther   = "Other"
+	FamilyBot     = "Bot"
+)
+
+// families перечислены в порядке проверки: многие браузеры добавляют
+// в User-Agent токены других (Edge, Opera и Chromium содержат "Chrome", Chrome содержит "Safari").
+var families = []struct {
+	token  string
+	family string
+}{
+	{"edg/", "Edge"},
+	{"edge/", "Edge"},
+	{"opr/", "Opera"},
+	{"opera", "Opera"},
+	{"yabrowser/", "Yandex Browser"},
+	{"samsungbrowser/", "Samsung Internet"},
+	{"firefox/", "Firefox"},
+	{"fxios/", "Firefox"},
+	{"chromium/", "Chromium"},
+	{"crios/", "Chrome"},
+	{"chrome/", "Chrome"},
+	{"msie ", "Internet Explorer"},
+	{"trident/", "Internet Explorer"},
+	{"safari/", "Safari"},
+	{"curl/", "curl"},
+	{"wget/", "Wget"},
+	{"postmanruntime/", "Postman"},
+	{"python-requests/", "python-requests"},
+	{"go-http-client/", "Go http client"},
+}
+
+var botTokens = []string{"bot", "crawler", "spider", "slurp", "facebookexternalhit"}
+
+// Family возвращает семейство клиента (браузер, бот, утилита) по строке User-Agent.
+func Family(ua string) string {
+	ua = strings.ToLower(strings.TrimSpace(ua))
+	if ua == "" {
+		return FamilyUnknown
+	}
+
+	for _, token := range botTokens {
+		if strings.Contains(ua, token) {
+			return FamilyBot
+		}
+	}
+
+	for _, f := range families {
+		if strings.Contains(ua, f.token) {
+			return f.family
+		}
+	}
+
+	return FamilyOther
+}
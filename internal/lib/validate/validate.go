@@ -0,0 +1,65 @@
+package validate
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// v проверяет тела запросов. Валидатор кэширует разбор структур и безопасен
+// для одновременного использования, поэтому создаётся один раз.
+var v = newValidator()
+
+// Struct проверяет поля s по тегам validate. Ошибки имеют тип validator.ValidationErrors,
+// где Field() возвращает имя поля в JSON, а StructField() — имя поля в Go.
+func Struct(s any) error {
+	return v.Struct(s)
+}
+
+// Validator проверяет структуры по тегам validate, включая собственные правила.
+// Правила добавляются до первой проверки: Validator не защищает их от одновременного изменения.
+type Validator struct {
+	v *validator.Validate
+}
+
+// New создаёт Validator со стандартными правилами.
+func New() *Validator {
+	return &Validator{v: newValidator()}
+}
+
+// RegisterString добавляет правило tag для строковых полей: значение проходит проверку, если fn возвращает true.
+func (v *Validator) RegisterString(tag string, fn func(s string) bool) {
+	err := v.v.RegisterValidation(tag, func(fl validator.FieldLevel) bool {
+		return fn(fl.Field().String())
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// RegisterAlias добавляет тег name, который раскрывается в набор правил tags, например "min=3,max=64".
+// В ошибках проверки ActualTag() возвращает нарушенное правило из набора.
+func (v *Validator) RegisterAlias(name, tags string) {
+	v.v.RegisterAlias(name, tags)
+}
+
+// Struct проверяет поля s так же, как функция Struct.
+func (v *Validator) Struct(s any) error {
+	return v.v.Struct(s)
+}
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			return f.Name
+		}
+
+		return name
+	})
+
+	return v
+}
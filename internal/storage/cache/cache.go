@@ -0,0 +1,237 @@
+// Package cache содержит кэш ссылок, который стоит перед хранилищем и
+// разгружает его на горячем пути редиректа.
+package cache
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"URLite/internal/storage"
+)
+
+// Backend — хранилище, перед которым стоит кэш.
+type Backend interface {
+	GetURLInfo(alias string) (storage.URL, error)
+	FindURL(owner, target string) (storage.URL, error)
+	SaveURL(u storage.URL) (int64, error)
+	UpdateURL(alias string, upd storage.URLUpdate) error
+	DeleteURL(alias string) error
+}
+
+// Options — параметры кэша.
+type Options struct {
+	// Size — максимальное число записей. При Size <= 0 кэш отключён.
+	Size int
+	// TTL — время жизни найденной ссылки в кэше.
+	TTL time.Duration
+	// NegativeTTL — время жизни записи о несуществующем псевдониме. При NegativeTTL <= 0 такие записи не кэшируются.
+	NegativeTTL time.Duration
+}
+
+// Stats — счётчики работы кэша.
+type Stats struct {
+	Hits      int64
+	Misses    int64
+	Evictions int64
+	Size      int
+}
+
+type entry struct {
+	alias    string
+	url      storage.URL
+	notFound bool
+	// cachedUntil — момент, после которого запись нужно перечитать из хранилища.
+	cachedUntil time.Time
+}
+
+// Cache — LRU-кэш ссылок с ограниченным размером и временем жизни записей.
+//
+// Изменения через SaveURL, UpdateURL и DeleteURL сбрасывают запись псевдонима,
+// поэтому через кэш должны проходить все изменения ссылок в этом процессе.
+type Cache struct {
+	backend Backend
+	opts    Options
+
+	mu      sync.Mutex
+	ll      *list.List
+	entries map[string]*list.Element
+	// generation увеличивается при каждом сбросе, чтобы не сохранить в кэш
+	// значение, прочитанное из хранилища до изменения ссылки.
+	generation uint64
+
+	hits      atomic.Int64
+	misses    atomic.Int64
+	evictions atomic.Int64
+}
+
+func New(backend Backend, opts Options) *Cache {
+	return &Cache{
+		backend: backend,
+		opts:    opts,
+		ll:      list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// GetURL возвращает адрес ссылки, обращаясь к хранилищу только при промахе кэша.
+func (c *Cache) GetURL(alias string) (string, error) {
+	const op = "storage.cache.GetURL"
+
+	now := time.Now()
+
+	if e, ok := c.lookup(alias, now); ok {
+		c.hits.Add(1)
+		return resolve(op, e, now)
+	}
+
+	c.misses.Add(1)
+
+	c.mu.Lock()
+	generation := c.generation
+	c.mu.Unlock()
+
+	u, err := c.backend.GetURLInfo(alias)
+
+	switch {
+	case errors.Is(err, storage.ErrURLNotFound):
+		if c.opts.NegativeTTL > 0 {
+			c.store(generation, &entry{alias: alias, notFound: true, cachedUntil: now.Add(c.opts.NegativeTTL)})
+		}
+		return "", fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+	case err != nil:
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	e := &entry{alias: alias, url: u, cachedUntil: now.Add(c.opts.TTL)}
+	c.store(generation, e)
+
+	return resolve(op, e, now)
+}
+
+func resolve(op string, e *entry, now time.Time) (string, error) {
+	if e.notFound {
+		return "", fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+	}
+
+	if e.url.Expired(now) {
+		return "", fmt.Errorf("%s: %w", op, storage.ErrURLExpired)
+	}
+
+	return e.url.URL, nil
+}
+
+// GetURLInfo читает ссылку со всеми метаданными напрямую из хранилища, минуя кэш.
+func (c *Cache) GetURLInfo(alias string) (storage.URL, error) {
+	return c.backend.GetURLInfo(alias)
+}
+
+// FindURL ищет ссылку владельца owner на адрес target напрямую в хранилище, минуя кэш.
+func (c *Cache) FindURL(owner, target string) (storage.URL, error) {
+	return c.backend.FindURL(owner, target)
+}
+
+// SaveURL сохраняет ссылку и сбрасывает закэшированное отсутствие её псевдонима.
+func (c *Cache) SaveURL(u storage.URL) (int64, error) {
+	defer c.Invalidate(u.Alias)
+
+	return c.backend.SaveURL(u)
+}
+
+// UpdateURL изменяет ссылку и сбрасывает её запись в кэше.
+// При переименовании сбрасывается и запись нового псевдонима, например закэшированное отсутствие ссылки.
+func (c *Cache) UpdateURL(alias string, upd storage.URLUpdate) error {
+	defer c.Invalidate(alias)
+	if upd.Alias != nil {
+		defer c.Invalidate(*upd.Alias)
+	}
+
+	return c.backend.UpdateURL(alias, upd)
+}
+
+// DeleteURL удаляет ссылку и сбрасывает её запись в кэше.
+func (c *Cache) DeleteURL(alias string) error {
+	defer c.Invalidate(alias)
+
+	return c.backend.DeleteURL(alias)
+}
+
+// Invalidate удаляет запись псевдонима из кэша.
+func (c *Cache) Invalidate(alias string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+
+	if el, ok := c.entries[alias]; ok {
+		c.ll.Remove(el)
+		delete(c.entries, alias)
+	}
+}
+
+// Stats возвращает текущие значения счётчиков кэша.
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	size := c.ll.Len()
+	c.mu.Unlock()
+
+	return Stats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+		Size:      size,
+	}
+}
+
+func (c *Cache) lookup(alias string, now time.Time) (*entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[alias]
+	if !ok {
+		return nil, false
+	}
+
+	e := el.Value.(*entry)
+	if !now.Before(e.cachedUntil) {
+		c.ll.Remove(el)
+		delete(c.entries, alias)
+		return nil, false
+	}
+
+	c.ll.MoveToFront(el)
+
+	return e, true
+}
+
+func (c *Cache) store(generation uint64, e *entry) {
+	if c.opts.Size <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// За время чтения из хранилища ссылка могла измениться
+	if c.generation != generation {
+		return
+	}
+
+	if el, ok := c.entries[e.alias]; ok {
+		el.Value = e
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	c.entries[e.alias] = c.ll.PushFront(e)
+
+	for c.ll.Len() > c.opts.Size {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.entries, oldest.Value.(*entry).alias)
+		c.evictions.Add(1)
+	}
+}
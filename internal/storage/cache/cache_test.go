@@ -0,0 +1,198 @@
+package cache_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"URLite/internal/storage"
+	"URLite/internal/storage/cache"
+	"URLite/internal/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingBackend считает обращения к хранилищу за ссылкой.
+type countingBackend struct {
+	*memory.Storage
+	loads atomic.Int64
+}
+
+func (b *countingBackend) GetURLInfo(alias string) (storage.URL, error) {
+	b.loads.Add(1)
+	return b.Storage.GetURLInfo(alias)
+}
+
+func newCache(t *testing.T, opts cache.Options) (*cache.Cache, *countingBackend) {
+	t.Helper()
+
+	backend := &countingBackend{Storage: memory.New()}
+
+	return cache.New(backend, opts), backend
+}
+
+func TestCache_GetURL_ReadThrough(t *testing.T) {
+	c, backend := newCache(t, cache.Options{Size: 10, TTL: time.Minute})
+
+	_, err := c.SaveURL(storage.URL{Alias: "go", URL: "https://go.dev"})
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		got, err := c.GetURL("go")
+		require.NoError(t, err)
+		assert.Equal(t, "https://go.dev", got)
+	}
+
+	assert.EqualValues(t, 1, backend.loads.Load())
+	assert.Equal(t, cache.Stats{Hits: 2, Misses: 1, Size: 1}, c.Stats())
+}
+
+func TestCache_GetURL_NegativeCaching(t *testing.T) {
+	c, backend := newCache(t, cache.Options{Size: 10, TTL: time.Minute, NegativeTTL: time.Minute})
+
+	for i := 0; i < 2; i++ {
+		_, err := c.GetURL("missing")
+		require.ErrorIs(t, err, storage.ErrURLNotFound)
+	}
+	assert.EqualValues(t, 1, backend.loads.Load())
+
+	// Создание ссылки сбрасывает закэшированное отсутствие псевдонима
+	_, err := c.SaveURL(storage.URL{Alias: "missing", URL: "https://go.dev"})
+	require.NoError(t, err)
+
+	got, err := c.GetURL("missing")
+	require.NoError(t, err)
+	assert.Equal(t, "https://go.dev", got)
+}
+
+func TestCache_GetURL_NegativeCachingDisabled(t *testing.T) {
+	// Отрицательный NegativeTTL приходит из конфигурации, где он отключает запоминание
+	c, backend := newCache(t, cache.Options{Size: 10, TTL: time.Minute, NegativeTTL: -time.Second})
+
+	for i := 0; i < 2; i++ {
+		_, err := c.GetURL("missing")
+		require.ErrorIs(t, err, storage.ErrURLNotFound)
+	}
+	assert.EqualValues(t, 2, backend.loads.Load())
+}
+
+func TestCache_Invalidation(t *testing.T) {
+	c, _ := newCache(t, cache.Options{Size: 10, TTL: time.Hour, NegativeTTL: time.Hour})
+
+	_, err := c.SaveURL(storage.URL{Alias: "go", URL: "https://go.dev"})
+	require.NoError(t, err)
+
+	_, err = c.GetURL("go")
+	require.NoError(t, err)
+
+	target := "https://go.dev/doc"
+	require.NoError(t, c.UpdateURL("go", storage.URLUpdate{URL: &target}))
+
+	got, err := c.GetURL("go")
+	require.NoError(t, err)
+	assert.Equal(t, target, got)
+
+	require.NoError(t, c.DeleteURL("go"))
+
+	_, err = c.GetURL("go")
+	require.ErrorIs(t, err, storage.ErrURLNotFound)
+}
+
+func TestCache_RenameInvalidatesBothAliases(t *testing.T) {
+	c, _ := newCache(t, cache.Options{Size: 10, TTL: time.Hour, NegativeTTL: time.Hour})
+
+	_, err := c.SaveURL(storage.URL{Alias: "tmp", URL: "https://go.dev"})
+	require.NoError(t, err)
+
+	_, err = c.GetURL("tmp")
+	require.NoError(t, err)
+	_, err = c.GetURL("go")
+	require.ErrorIs(t, err, storage.ErrURLNotFound)
+
+	newAlias := "go"
+	require.NoError(t, c.UpdateURL("tmp", storage.URLUpdate{Alias: &newAlias}))
+
+	_, err = c.GetURL("tmp")
+	require.ErrorIs(t, err, storage.ErrURLNotFound)
+
+	got, err := c.GetURL("go")
+	require.NoError(t, err)
+	assert.Equal(t, "https://go.dev", got)
+}
+
+func TestCache_TTL(t *testing.T) {
+	c, backend := newCache(t, cache.Options{Size: 10, TTL: time.Millisecond})
+
+	_, err := c.SaveURL(storage.URL{Alias: "go", URL: "https://go.dev"})
+	require.NoError(t, err)
+
+	_, err = c.GetURL("go")
+	require.NoError(t, err)
+
+	time.Sleep(5 * time.Millisecond)
+
+	_, err = c.GetURL("go")
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, backend.loads.Load())
+}
+
+func TestCache_LinkExpiration(t *testing.T) {
+	c, _ := newCache(t, cache.Options{Size: 10, TTL: time.Hour})
+
+	expiresAt := time.Now().Add(50 * time.Millisecond)
+	_, err := c.SaveURL(storage.URL{Alias: "promo", URL: "https://go.dev", ExpiresAt: &expiresAt})
+	require.NoError(t, err)
+
+	_, err = c.GetURL("promo")
+	require.NoError(t, err)
+
+	time.Sleep(time.Until(expiresAt) + 5*time.Millisecond)
+
+	// Запись ещё в кэше, но ссылка уже истекла
+	_, err = c.GetURL("promo")
+	require.ErrorIs(t, err, storage.ErrURLExpired)
+	assert.EqualValues(t, 1, c.Stats().Hits)
+}
+
+func TestCache_Eviction(t *testing.T) {
+	c, backend := newCache(t, cache.Options{Size: 2, TTL: time.Hour})
+
+	for _, alias := range []string{"a", "b", "c"} {
+		_, err := c.SaveURL(storage.URL{Alias: alias, URL: "https://go.dev/" + alias})
+		require.NoError(t, err)
+	}
+
+	for _, alias := range []string{"a", "b", "a", "c"} {
+		_, err := c.GetURL(alias)
+		require.NoError(t, err)
+	}
+
+	stats := c.Stats()
+	assert.EqualValues(t, 1, stats.Evictions)
+	assert.Equal(t, 2, stats.Size)
+
+	// "b" вытеснена как давно не использовавшаяся, "a" осталась в кэше
+	loads := backend.loads.Load()
+	_, err := c.GetURL("a")
+	require.NoError(t, err)
+	assert.Equal(t, loads, backend.loads.Load())
+
+	_, err = c.GetURL("b")
+	require.NoError(t, err)
+	assert.Equal(t, loads+1, backend.loads.Load())
+}
+
+func TestCache_Disabled(t *testing.T) {
+	c, backend := newCache(t, cache.Options{Size: -1, TTL: time.Minute})
+
+	_, err := c.SaveURL(storage.URL{Alias: "go", URL: "https://go.dev"})
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err := c.GetURL("go")
+		require.NoError(t, err)
+	}
+
+	assert.EqualValues(t, 2, backend.loads.Load())
+	assert.Zero(t, c.Stats().Size)
+}
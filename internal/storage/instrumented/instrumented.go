@@ -0,0 +1,229 @@
+// Package instrumented содержит обёртку хранилища, которая измеряет
+// длительность каждой операции и считает ошибки.
+package instrumented
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"URLite/internal/storage"
+)
+
+// Backend — хранилище, операции которого измеряются.
+type Backend interface {
+	SaveURL(u storage.URL) (int64, error)
+	GetURL(alias string) (string, error)
+	GetURLInfo(alias string) (storage.URL, error)
+	FindURL(owner, target string) (storage.URL, error)
+	ListURLs(params storage.ListParams) (storage.URLPage, error)
+	UpdateURL(alias string, upd storage.URLUpdate) error
+	DeleteURL(alias string) error
+	DeleteExpired(now time.Time) (int64, error)
+	SaveClicks(clicks []storage.Click) error
+	GetURLStats(alias string, q storage.StatsQuery) (storage.URLStats, error)
+	SaveAPIKey(k storage.APIKey) (int64, error)
+	GetAPIKey(hash string) (storage.APIKey, error)
+	ListAPIKeys() ([]storage.APIKey, error)
+	RevokeAPIKey(id int64, at time.Time) error
+	SaveUser(u storage.User) (int64, error)
+	GetUser(username string) (storage.User, error)
+	ListUsers() ([]storage.User, error)
+	DeleteUser(username string) error
+	Ping(ctx context.Context) error
+	Close() error
+}
+
+// Observer — получатель метрик операций хранилища.
+type Observer interface {
+	ObserveStorageOp(op string, d time.Duration, failed bool)
+}
+
+// Storage передаёт вызовы в Backend и сообщает Observer длительность и результат
+// каждой операции под именем вида "storage.<driver>.<Method>".
+type Storage struct {
+	backend  Backend
+	observer Observer
+	prefix   string
+}
+
+func New(backend Backend, driver string, observer Observer) *Storage {
+	return &Storage{
+		backend:  backend,
+		observer: observer,
+		prefix:   "storage." + driver + ".",
+	}
+}
+
+// failed сообщает, считать ли err сбоем хранилища. Ожидаемые ответы вроде
+// отсутствующей ссылки или занятого псевдонима сбоями не считаются.
+func failed(err error) bool {
+	return err != nil &&
+		!errors.Is(err, storage.ErrURLNotFound) &&
+		!errors.Is(err, storage.ErrURLExists) &&
+		!errors.Is(err, storage.ErrInvalidCursor) &&
+		!errors.Is(err, storage.ErrAPIKeyNotFound) &&
+		!errors.Is(err, storage.ErrUserNotFound) &&
+		!errors.Is(err, storage.ErrUserExists)
+}
+
+func (s *Storage) observe(method string, start time.Time, err error) {
+	s.observer.ObserveStorageOp(s.prefix+method, time.Since(start), failed(err))
+}
+
+func (s *Storage) SaveURL(u storage.URL) (int64, error) {
+	start := time.Now()
+	id, err := s.backend.SaveURL(u)
+	s.observe("SaveURL", start, err)
+
+	return id, err
+}
+
+func (s *Storage) GetURL(alias string) (string, error) {
+	start := time.Now()
+	u, err := s.backend.GetURL(alias)
+	s.observe("GetURL", start, err)
+
+	return u, err
+}
+
+func (s *Storage) GetURLInfo(alias string) (storage.URL, error) {
+	start := time.Now()
+	u, err := s.backend.GetURLInfo(alias)
+	s.observe("GetURLInfo", start, err)
+
+	return u, err
+}
+
+func (s *Storage) FindURL(owner, target string) (storage.URL, error) {
+	start := time.Now()
+	u, err := s.backend.FindURL(owner, target)
+	s.observe("FindURL", start, err)
+
+	return u, err
+}
+
+func (s *Storage) ListURLs(params storage.ListParams) (storage.URLPage, error) {
+	start := time.Now()
+	page, err := s.backend.ListURLs(params)
+	s.observe("ListURLs", start, err)
+
+	return page, err
+}
+
+func (s *Storage) UpdateURL(alias string, upd storage.URLUpdate) error {
+	start := time.Now()
+	err := s.backend.UpdateURL(alias, upd)
+	s.observe("UpdateURL", start, err)
+
+	return err
+}
+
+func (s *Storage) DeleteURL(alias string) error {
+	start := time.Now()
+	err := s.backend.DeleteURL(alias)
+	s.observe("DeleteURL", start, err)
+
+	return err
+}
+
+func (s *Storage) DeleteExpired(now time.Time) (int64, error) {
+	start := time.Now()
+	n, err := s.backend.DeleteExpired(now)
+	s.observe("DeleteExpired", start, err)
+
+	return n, err
+}
+
+func (s *Storage) SaveClicks(clicks []storage.Click) error {
+	start := time.Now()
+	err := s.backend.SaveClicks(clicks)
+	s.observe("SaveClicks", start, err)
+
+	return err
+}
+
+func (s *Storage) GetURLStats(alias string, q storage.StatsQuery) (storage.URLStats, error) {
+	start := time.Now()
+	st, err := s.backend.GetURLStats(alias, q)
+	s.observe("GetURLStats", start, err)
+
+	return st, err
+}
+
+func (s *Storage) SaveAPIKey(k storage.APIKey) (int64, error) {
+	start := time.Now()
+	id, err := s.backend.SaveAPIKey(k)
+	s.observe("SaveAPIKey", start, err)
+
+	return id, err
+}
+
+func (s *Storage) GetAPIKey(hash string) (storage.APIKey, error) {
+	start := time.Now()
+	k, err := s.backend.GetAPIKey(hash)
+	s.observe("GetAPIKey", start, err)
+
+	return k, err
+}
+
+func (s *Storage) ListAPIKeys() ([]storage.APIKey, error) {
+	start := time.Now()
+	keys, err := s.backend.ListAPIKeys()
+	s.observe("ListAPIKeys", start, err)
+
+	return keys, err
+}
+
+func (s *Storage) RevokeAPIKey(id int64, at time.Time) error {
+	start := time.Now()
+	err := s.backend.RevokeAPIKey(id, at)
+	s.observe("RevokeAPIKey", start, err)
+
+	return err
+}
+
+func (s *Storage) SaveUser(u storage.User) (int64, error) {
+	start := time.Now()
+	id, err := s.backend.SaveUser(u)
+	s.observe("SaveUser", start, err)
+
+	return id, err
+}
+
+func (s *Storage) GetUser(username string) (storage.User, error) {
+	start := time.Now()
+	u, err := s.backend.GetUser(username)
+	s.observe("GetUser", start, err)
+
+	return u, err
+}
+
+func (s *Storage) ListUsers() ([]storage.User, error) {
+	start := time.Now()
+	users, err := s.backend.ListUsers()
+	s.observe("ListUsers", start, err)
+
+	return users, err
+}
+
+func (s *Storage) DeleteUser(username string) error {
+	start := time.Now()
+	err := s.backend.DeleteUser(username)
+	s.observe("DeleteUser", start, err)
+
+	return err
+}
+
+func (s *Storage) Ping(ctx context.Context) error {
+	start := time.Now()
+	err := s.backend.Ping(ctx)
+	s.observe("Ping", start, err)
+
+	return err
+}
+
+// Close закрывает хранилище. Операция не измеряется.
+func (s *Storage) Close() error {
+	return s.backend.Close()
+}
@@ -0,0 +1,57 @@
+package instrumented_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"URLite/internal/storage"
+	"URLite/internal/storage/instrumented"
+	"URLite/internal/storage/memory"
+	"github.com/stretchr/testify/require"
+)
+
+type observation struct {
+	op     string
+	failed bool
+}
+
+type observerStub struct {
+	observations []observation
+}
+
+func (o *observerStub) ObserveStorageOp(op string, _ time.Duration, failed bool) {
+	o.observations = append(o.observations, observation{op: op, failed: failed})
+}
+
+// failingBackend отвечает ошибкой на удаление ссылок.
+type failingBackend struct {
+	*memory.Storage
+}
+
+func (failingBackend) DeleteURL(string) error {
+	return errors.New("disk I/O error")
+}
+
+func TestStorage(t *testing.T) {
+	observer := &observerStub{}
+	s := instrumented.New(failingBackend{Storage: memory.New()}, "memory", observer)
+
+	_, err := s.SaveURL(storage.URL{Alias: "go", URL: "https://go.dev"})
+	require.NoError(t, err)
+
+	_, err = s.SaveURL(storage.URL{Alias: "go", URL: "https://go.dev"})
+	require.ErrorIs(t, err, storage.ErrURLExists)
+
+	_, err = s.GetURL("missing")
+	require.ErrorIs(t, err, storage.ErrURLNotFound)
+
+	require.Error(t, s.DeleteURL("go"))
+
+	require.Equal(t, []observation{
+		{op: "storage.memory.SaveURL"},
+		{op: "storage.memory.SaveURL"},
+		{op: "storage.memory.GetURL"},
+		{op: "storage.memory.DeleteURL", failed: true},
+	}, observer.observations)
+}
@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"URLite/internal/storage"
+)
+
+// SaveAPIKey сохраняет новый ключ доступа и возвращает его идентификатор.
+func (s *Storage) SaveAPIKey(k storage.APIKey) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastKeyID++
+	k.ID = s.lastKeyID
+	k.CreatedAt = k.CreatedAt.UTC()
+	k.RevokedAt = copyTime(k.RevokedAt)
+	s.apiKeys[k.ID] = k
+
+	return k.ID, nil
+}
+
+// GetAPIKey возвращает ключ доступа по хешу, в том числе отозванный.
+func (s *Storage) GetAPIKey(hash string) (storage.APIKey, error) {
+	const op = "storage.memory.GetAPIKey"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, k := range s.apiKeys {
+		if k.Hash == hash {
+			k.RevokedAt = copyTime(k.RevokedAt)
+			return k, nil
+		}
+	}
+
+	return storage.APIKey{}, fmt.Errorf("%s: %w", op, storage.ErrAPIKeyNotFound)
+}
+
+// ListAPIKeys возвращает все ключи доступа в порядке создания.
+func (s *Storage) ListAPIKeys() ([]storage.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]storage.APIKey, 0, len(s.apiKeys))
+	for _, k := range s.apiKeys {
+		k.RevokedAt = copyTime(k.RevokedAt)
+		keys = append(keys, k)
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+
+	return keys, nil
+}
+
+// RevokeAPIKey отзывает ключ доступа. Повторный отзыв не меняет момент отзыва.
+func (s *Storage) RevokeAPIKey(id int64, at time.Time) error {
+	const op = "storage.memory.RevokeAPIKey"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k, ok := s.apiKeys[id]
+	if !ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrAPIKeyNotFound)
+	}
+
+	if k.RevokedAt == nil {
+		k.RevokedAt = copyTime(&at)
+		s.apiKeys[id] = k
+	}
+
+	return nil
+}
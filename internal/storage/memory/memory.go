@@ -0,0 +1,399 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"URLite/internal/storage"
+)
+
+// Storage — потокобезопасное хранилище ссылок в памяти процесса.
+// Подходит для тестов и временных окружений: данные теряются при остановке сервиса.
+type Storage struct {
+	mu         sync.RWMutex
+	lastID     int64
+	urls       map[string]storage.URL
+	clicks     map[string][]storage.Click
+	lastKeyID  int64
+	apiKeys    map[int64]storage.APIKey
+	lastUserID int64
+	users      map[string]storage.User
+}
+
+func New() *Storage {
+	return &Storage{
+		urls:    make(map[string]storage.URL),
+		clicks:  make(map[string][]storage.Click),
+		apiKeys: make(map[int64]storage.APIKey),
+		users:   make(map[string]storage.User),
+	}
+}
+
+// Ping всегда успешен: хранилище в памяти доступно, пока жив процесс.
+func (s *Storage) Ping(context.Context) error {
+	return nil
+}
+
+// Close ничего не делает: хранилищу в памяти нечего освобождать.
+func (s *Storage) Close() error {
+	return nil
+}
+
+// copyTime возвращает копию t, чтобы хранилище не разделяло память с вызывающим кодом.
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	c := t.UTC()
+
+	return &c
+}
+
+// SaveURL сохраняет новую ссылку. Поле ID у u игнорируется, идентификатор записи возвращается.
+func (s *Storage) SaveURL(u storage.URL) (int64, error) {
+	const op = "storage.memory.SaveURL"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.urls[u.Alias]; ok {
+		return 0, fmt.Errorf("%s: %w", op, storage.ErrURLExists)
+	}
+
+	s.lastID++
+	u.ID = s.lastID
+	u.ExpiresAt = copyTime(u.ExpiresAt)
+	s.urls[u.Alias] = u
+
+	return u.ID, nil
+}
+
+func (s *Storage) GetURL(alias string) (string, error) {
+	const op = "storage.memory.GetURL"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	u, ok := s.urls[alias]
+	if !ok {
+		return "", fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+	}
+
+	if u.Expired(time.Now()) {
+		return "", fmt.Errorf("%s: %w", op, storage.ErrURLExpired)
+	}
+
+	return u.URL, nil
+}
+
+// GetURLInfo возвращает ссылку с псевдонимом alias со всеми метаданными.
+func (s *Storage) GetURLInfo(alias string) (storage.URL, error) {
+	const op = "storage.memory.GetURLInfo"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	u, ok := s.urls[alias]
+	if !ok {
+		return storage.URL{}, fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+	}
+
+	u.ExpiresAt = copyTime(u.ExpiresAt)
+
+	return u, nil
+}
+
+// FindURL возвращает самую раннюю действующую ссылку владельца owner на адрес target.
+func (s *Storage) FindURL(owner, target string) (storage.URL, error) {
+	const op = "storage.memory.FindURL"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+
+	var (
+		found storage.URL
+		ok    bool
+	)
+	for _, u := range s.urls {
+		if u.Owner != owner || u.URL != target || u.Expired(now) {
+			continue
+		}
+		if !ok || u.ID < found.ID {
+			found, ok = u, true
+		}
+	}
+
+	if !ok {
+		return storage.URL{}, fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+	}
+
+	found.ExpiresAt = copyTime(found.ExpiresAt)
+
+	return found, nil
+}
+
+// ListURLs возвращает страницу ссылок, отфильтрованных и отсортированных согласно params.
+func (s *Storage) ListURLs(params storage.ListParams) (storage.URLPage, error) {
+	const op = "storage.memory.ListURLs"
+
+	var cursor storage.Cursor
+	if params.Cursor != "" {
+		c, err := storage.DecodeCursor(params.Cursor)
+		if err != nil {
+			return storage.URLPage{}, fmt.Errorf("%s: %w", op, err)
+		}
+		cursor = c
+	}
+
+	byAlias := params.SortBy == storage.SortByAlias
+
+	// less сообщает, идёт ли ссылка a раньше b в выбранном порядке сортировки.
+	less := func(a, b storage.Cursor) bool {
+		if byAlias {
+			return a.Alias < b.Alias
+		}
+		return a.ID < b.ID
+	}
+	if params.Desc {
+		asc := less
+		less = func(a, b storage.Cursor) bool { return asc(b, a) }
+	}
+
+	host := strings.ToLower(params.Host)
+
+	s.mu.RLock()
+
+	urls := make([]storage.URL, 0, len(s.urls))
+	for _, u := range s.urls {
+		if params.AliasPrefix != "" && !strings.HasPrefix(u.Alias, params.AliasPrefix) {
+			continue
+		}
+		if host != "" && urlHost(u.URL) != host {
+			continue
+		}
+		if params.Cursor != "" && !less(cursor, storage.Cursor{ID: u.ID, Alias: u.Alias}) {
+			continue
+		}
+
+		u.ExpiresAt = copyTime(u.ExpiresAt)
+		urls = append(urls, u)
+	}
+
+	s.mu.RUnlock()
+
+	sort.Slice(urls, func(i, j int) bool {
+		return less(
+			storage.Cursor{ID: urls[i].ID, Alias: urls[i].Alias},
+			storage.Cursor{ID: urls[j].ID, Alias: urls[j].Alias},
+		)
+	})
+
+	page := storage.URLPage{URLs: urls}
+
+	if len(urls) > params.Limit {
+		page.URLs = urls[:params.Limit]
+		page.NextCursor = storage.EncodeCursor(page.URLs[len(page.URLs)-1])
+	}
+
+	return page, nil
+}
+
+// urlHost возвращает имя хоста из URL в нижнем регистре.
+func urlHost(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+
+	return strings.ToLower(u.Hostname())
+}
+
+// UpdateURL атомарно изменяет поля ссылки с указанным псевдонимом.
+// При смене псевдонима переходы по ссылке переносятся вместе с ней.
+func (s *Storage) UpdateURL(alias string, upd storage.URLUpdate) error {
+	const op = "storage.memory.UpdateURL"
+
+	if upd == (storage.URLUpdate{}) {
+		return fmt.Errorf("%s: nothing to update", op)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.urls[alias]
+	if !ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+	}
+
+	if upd.Alias != nil && *upd.Alias != alias {
+		if _, ok := s.urls[*upd.Alias]; ok {
+			return fmt.Errorf("%s: %w", op, storage.ErrURLExists)
+		}
+
+		delete(s.urls, alias)
+		u.Alias = *upd.Alias
+
+		if clicks, ok := s.clicks[alias]; ok {
+			for i := range clicks {
+				clicks[i].Alias = u.Alias
+			}
+			s.clicks[u.Alias] = clicks
+			delete(s.clicks, alias)
+		}
+	}
+	if upd.URL != nil {
+		u.URL = *upd.URL
+	}
+	if upd.OriginalURL != nil {
+		u.OriginalURL = *upd.OriginalURL
+	}
+	if upd.ClearExpiresAt {
+		u.ExpiresAt = nil
+	} else if upd.ExpiresAt != nil {
+		u.ExpiresAt = copyTime(upd.ExpiresAt)
+	}
+
+	s.urls[u.Alias] = u
+
+	return nil
+}
+
+// DeleteURL удаляет ссылку вместе с её переходами, чтобы новая ссылка
+// с тем же псевдонимом не унаследовала чужую статистику.
+func (s *Storage) DeleteURL(alias string) error {
+	const op = "storage.memory.DeleteURL"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.urls[alias]; !ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+	}
+
+	delete(s.urls, alias)
+	delete(s.clicks, alias)
+
+	return nil
+}
+
+// DeleteExpired удаляет ссылки, срок действия которых истёк к моменту now, вместе с их переходами.
+// Возвращает число удалённых ссылок.
+func (s *Storage) DeleteExpired(now time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+
+	for alias, u := range s.urls {
+		if u.Expired(now) {
+			delete(s.urls, alias)
+			delete(s.clicks, alias)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+// SaveClicks сохраняет пачку событий переходов.
+// Переходы по ссылкам, удалённым до сохранения пачки, отбрасываются.
+func (s *Storage) SaveClicks(clicks []storage.Click) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, c := range clicks {
+		if _, ok := s.urls[c.Alias]; !ok {
+			continue
+		}
+		s.clicks[c.Alias] = append(s.clicks[c.Alias], c)
+	}
+
+	return nil
+}
+
+// GetURLStats возвращает статистику переходов по ссылке за период, заданный в q.
+func (s *Storage) GetURLStats(alias string, q storage.StatsQuery) (storage.URLStats, error) {
+	const op = "storage.memory.GetURLStats"
+
+	var stats storage.URLStats
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.urls[alias]; !ok {
+		return stats, fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+	}
+
+	step := storage.IntervalSeconds(q.Interval)
+
+	var (
+		visitors   = make(map[string]struct{})
+		buckets    = make(map[int64]int64)
+		referrers  = make(map[string]int64)
+		userAgents = make(map[string]int64)
+	)
+
+	for _, c := range s.clicks[alias] {
+		if c.Timestamp.Before(q.From) || !c.Timestamp.Before(q.To) {
+			continue
+		}
+
+		stats.TotalClicks++
+		visitors[c.RemoteIP+"|"+c.UserAgent] = struct{}{}
+		buckets[c.Timestamp.Unix()/step*step]++
+		userAgents[c.UserAgent]++
+		if c.Referrer != "" {
+			referrers[c.Referrer]++
+		}
+	}
+
+	stats.UniqueVisitors = int64(len(visitors))
+
+	for start, clicks := range buckets {
+		stats.Series = append(stats.Series, storage.StatsBucket{
+			Start:  time.Unix(start, 0).UTC(),
+			Clicks: clicks,
+		})
+	}
+	sort.Slice(stats.Series, func(i, j int) bool {
+		return stats.Series[i].Start.Before(stats.Series[j].Start)
+	})
+
+	stats.TopReferrers = sortedCounts(referrers)
+	if len(stats.TopReferrers) > q.Top {
+		stats.TopReferrers = stats.TopReferrers[:q.Top]
+	}
+
+	stats.UserAgents = sortedCounts(userAgents)
+
+	return stats, nil
+}
+
+// sortedCounts упорядочивает значения по убыванию числа переходов.
+func sortedCounts(counts map[string]int64) []storage.StatsCount {
+	if len(counts) == 0 {
+		return nil
+	}
+
+	res := make([]storage.StatsCount, 0, len(counts))
+	for value, count := range counts {
+		res = append(res, storage.StatsCount{Value: value, Count: count})
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Count != res[j].Count {
+			return res[i].Count > res[j].Count
+		}
+		return res[i].Value < res[j].Value
+	})
+
+	return res
+}
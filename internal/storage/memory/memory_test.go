@@ -0,0 +1,14 @@
+package memory_test
+
+import (
+	"testing"
+
+	"URLite/internal/storage/memory"
+	"URLite/internal/storage/storagetest"
+)
+
+func TestStorage(t *testing.T) {
+	storagetest.RunSuite(t, func(t *testing.T) storagetest.Storage {
+		return memory.New()
+	})
+}
@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+
+	"URLite/internal/storage"
+)
+
+// SaveUser сохраняет нового пользователя и возвращает его идентификатор.
+func (s *Storage) SaveUser(u storage.User) (int64, error) {
+	const op = "storage.memory.SaveUser"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[u.Username]; ok {
+		return 0, fmt.Errorf("%s: %w", op, storage.ErrUserExists)
+	}
+
+	s.lastUserID++
+	u.ID = s.lastUserID
+	u.CreatedAt = u.CreatedAt.UTC()
+	s.users[u.Username] = u
+
+	return u.ID, nil
+}
+
+// GetUser возвращает пользователя по имени.
+func (s *Storage) GetUser(username string) (storage.User, error) {
+	const op = "storage.memory.GetUser"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	u, ok := s.users[username]
+	if !ok {
+		return storage.User{}, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	return u, nil
+}
+
+// ListUsers возвращает всех пользователей в порядке создания.
+func (s *Storage) ListUsers() ([]storage.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	users := make([]storage.User, 0, len(s.users))
+	for _, u := range s.users {
+		users = append(users, u)
+	}
+
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+
+	return users, nil
+}
+
+// DeleteUser удаляет пользователя по имени. Ссылки пользователя не удаляются, а остаются без владельца,
+// чтобы их не унаследовал новый пользователь с тем же именем.
+func (s *Storage) DeleteUser(username string) error {
+	const op = "storage.memory.DeleteUser"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[username]; !ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	delete(s.users, username)
+
+	for alias, u := range s.urls {
+		if u.Owner == username {
+			u.Owner = ""
+			s.urls[alias] = u
+		}
+	}
+
+	return nil
+}
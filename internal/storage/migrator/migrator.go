@@ -0,0 +1,260 @@
+// Package migrator применяет и откатывает версионированные SQL-миграции схемы базы данных.
+//
+// Миграции хранятся в файлах вида 0001_create_url.up.sql и 0001_create_url.down.sql
+// и применяются по возрастанию версии. Применённые версии записываются в таблицу
+// schema_migrations. Запросы используют плейсхолдеры "?" (SQLite).
+package migrator
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrNoApplied    = errors.New("no applied migrations")
+	ErrIrreversible = errors.New("migration has no down script")
+)
+
+var fileRe = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration — одна версия схемы.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status — состояние миграции. AppliedAt равен nil, если миграция ещё не применена.
+type Status struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// New читает миграции из корня fsys.
+func New(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	const op = "storage.migrator.New"
+
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	byVersion := make(map[int]*Migration)
+
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+
+		m := fileRe.FindStringSubmatch(entry.Name())
+		if m == nil {
+			return nil, fmt.Errorf("%s: invalid migration file name %q", op, entry.Name())
+		}
+
+		version, err := strconv.Atoi(m[1])
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("%s: invalid migration version in %q", op, entry.Name())
+		}
+
+		script, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		}
+		if mig.Name != m[2] {
+			return nil, fmt.Errorf("%s: migration %d has different names: %q and %q", op, version, mig.Name, m[2])
+		}
+
+		if m[3] == "up" {
+			mig.Up = string(script)
+		} else {
+			mig.Down = string(script)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" {
+			return nil, fmt.Errorf("%s: migration %d_%s has no up script", op, mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+func (m *Migrator) ensureTable() error {
+	_, err := m.db.Exec(`
+	CREATE TABLE IF NOT EXISTS schema_migrations(
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at INTEGER NOT NULL)`)
+
+	return err
+}
+
+// applied возвращает время применения каждой применённой версии.
+func (m *Migrator) applied() (map[int]time.Time, error) {
+	if err := m.ensureTable(); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.Query("SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	applied := make(map[int]time.Time)
+
+	for rows.Next() {
+		var version, appliedAt int64
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+
+		applied[int(version)] = time.Unix(appliedAt, 0).UTC()
+	}
+
+	return applied, rows.Err()
+}
+
+// Up применяет все ещё не применённые миграции и возвращает их список.
+func (m *Migrator) Up() ([]Migration, error) {
+	const op = "storage.migrator.Up"
+
+	applied, err := m.applied()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var done []Migration
+
+	for _, mig := range m.migrations {
+		if _, ok := applied[mig.Version]; ok {
+			continue
+		}
+
+		err := m.inTx(mig.Up, "INSERT INTO schema_migrations(version, name, applied_at) VALUES(?, ?, ?)",
+			mig.Version, mig.Name, time.Now().Unix())
+		if err != nil {
+			return done, fmt.Errorf("%s: migration %d_%s: %w", op, mig.Version, mig.Name, err)
+		}
+
+		done = append(done, mig)
+	}
+
+	return done, nil
+}
+
+// Down откатывает последнюю применённую миграцию и возвращает её.
+func (m *Migrator) Down() (Migration, error) {
+	const op = "storage.migrator.Down"
+
+	applied, err := m.applied()
+	if err != nil {
+		return Migration{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		mig := m.migrations[i]
+
+		if _, ok := applied[mig.Version]; !ok {
+			continue
+		}
+
+		if mig.Down == "" {
+			return mig, fmt.Errorf("%s: migration %d_%s: %w", op, mig.Version, mig.Name, ErrIrreversible)
+		}
+
+		if err := m.inTx(mig.Down, "DELETE FROM schema_migrations WHERE version = ?", mig.Version); err != nil {
+			return mig, fmt.Errorf("%s: migration %d_%s: %w", op, mig.Version, mig.Name, err)
+		}
+
+		return mig, nil
+	}
+
+	return Migration{}, fmt.Errorf("%s: %w", op, ErrNoApplied)
+}
+
+// Status возвращает состояние всех известных миграций по возрастанию версии.
+func (m *Migrator) Status() ([]Status, error) {
+	const op = "storage.migrator.Status"
+
+	applied, err := m.applied()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+
+	for _, mig := range m.migrations {
+		s := Status{Version: mig.Version, Name: mig.Name}
+		if t, ok := applied[mig.Version]; ok {
+			s.AppliedAt = &t
+		}
+
+		statuses = append(statuses, s)
+	}
+
+	return statuses, nil
+}
+
+// Pending возвращает число ещё не применённых миграций.
+func (m *Migrator) Pending() (int, error) {
+	statuses, err := m.Status()
+	if err != nil {
+		return 0, err
+	}
+
+	pending := 0
+	for _, s := range statuses {
+		if s.AppliedAt == nil {
+			pending++
+		}
+	}
+
+	return pending, nil
+}
+
+// inTx выполняет скрипт миграции и изменение schema_migrations в одной транзакции.
+func (m *Migrator) inTx(script string, bookkeeping string, args ...any) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(script); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(bookkeeping, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,143 @@
+package migrator_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	_ "github.com/mattn/go-sqlite3" // init sqlite3 driver
+	"github.com/stretchr/testify/require"
+
+	"URLite/internal/storage/migrator"
+)
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	return db
+}
+
+func file(data string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(data)}
+}
+
+func tableExists(t *testing.T, db *sql.DB, name string) bool {
+	t.Helper()
+
+	var exists bool
+	err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = ?)", name).Scan(&exists)
+	require.NoError(t, err)
+
+	return exists
+}
+
+func TestMigrator_UpDownStatus(t *testing.T) {
+	db := openDB(t)
+
+	fsys := fstest.MapFS{
+		"0002_create_b.up.sql":   file("CREATE TABLE b(id INTEGER);"),
+		"0002_create_b.down.sql": file("DROP TABLE b;"),
+		"0001_create_a.up.sql":   file("CREATE TABLE a(id INTEGER); CREATE INDEX idx_a ON a(id);"),
+		"0001_create_a.down.sql": file("DROP TABLE a;"),
+		"README.md":              file("not a migration"),
+	}
+
+	m, err := migrator.New(db, fsys)
+	require.NoError(t, err)
+
+	applied, err := m.Up()
+	require.NoError(t, err)
+	require.Len(t, applied, 2)
+	require.Equal(t, 1, applied[0].Version, "migrations must be applied in version order")
+	require.Equal(t, "create_a", applied[0].Name)
+	require.True(t, tableExists(t, db, "a"))
+	require.True(t, tableExists(t, db, "b"))
+
+	// Повторный запуск ничего не применяет
+	applied, err = m.Up()
+	require.NoError(t, err)
+	require.Empty(t, applied)
+
+	rolledBack, err := m.Down()
+	require.NoError(t, err)
+	require.Equal(t, 2, rolledBack.Version)
+	require.False(t, tableExists(t, db, "b"))
+
+	statuses, err := m.Status()
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	require.NotNil(t, statuses[0].AppliedAt)
+	require.Nil(t, statuses[1].AppliedAt)
+
+	pending, err := m.Pending()
+	require.NoError(t, err)
+	require.Equal(t, 1, pending)
+
+	_, err = m.Down()
+	require.NoError(t, err)
+
+	_, err = m.Down()
+	require.ErrorIs(t, err, migrator.ErrNoApplied)
+}
+
+func TestMigrator_FailedMigrationIsRolledBack(t *testing.T) {
+	db := openDB(t)
+
+	fsys := fstest.MapFS{
+		"0001_broken.up.sql": file("CREATE TABLE a(id INTEGER); INSERT INTO missing VALUES(1);"),
+	}
+
+	m, err := migrator.New(db, fsys)
+	require.NoError(t, err)
+
+	_, err = m.Up()
+	require.Error(t, err)
+	require.False(t, tableExists(t, db, "a"))
+
+	pending, err := m.Pending()
+	require.NoError(t, err)
+	require.Equal(t, 1, pending)
+}
+
+func TestMigrator_Irreversible(t *testing.T) {
+	db := openDB(t)
+
+	m, err := migrator.New(db, fstest.MapFS{
+		"0001_create_a.up.sql": file("CREATE TABLE a(id INTEGER);"),
+	})
+	require.NoError(t, err)
+
+	_, err = m.Up()
+	require.NoError(t, err)
+
+	_, err = m.Down()
+	require.ErrorIs(t, err, migrator.ErrIrreversible)
+	require.True(t, tableExists(t, db, "a"))
+}
+
+func TestNew_InvalidFiles(t *testing.T) {
+	cases := map[string]fstest.MapFS{
+		"Bad name": {
+			"create_a.up.sql": file("CREATE TABLE a(id INTEGER);"),
+		},
+		"Missing up": {
+			"0001_create_a.down.sql": file("DROP TABLE a;"),
+		},
+		"Name mismatch": {
+			"0001_create_a.up.sql":   file("CREATE TABLE a(id INTEGER);"),
+			"0001_create_b.down.sql": file("DROP TABLE b;"),
+		},
+	}
+
+	for name, fsys := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := migrator.New(openDB(t), fsys)
+			require.Error(t, err)
+		})
+	}
+}
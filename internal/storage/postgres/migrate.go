@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// migration — одноразовое изменение данных, которое нельзя повторять при каждом запуске,
+// в отличие от идемпотентного создания схемы в New.
+type migration struct {
+	version int
+	name    string
+	script  string
+}
+
+// migrations применяются по возрастанию версии, новые добавляются в конец списка.
+var migrations = []migration{
+	{
+		version: 1,
+		name:    "delete_orphaned_clicks",
+		// Новые переходы без ссылки не появляются благодаря проверке в SaveClicks
+		script: `DELETE FROM clicks WHERE NOT EXISTS(SELECT 1 FROM url WHERE url.alias = clicks.alias)`,
+	},
+	{
+		version: 2,
+		name:    "namespace_api_key_owners",
+		// Ссылки, созданные ключами до появления префикса key:, переходят к владельцу ключа.
+		// Владельцы, совпадающие с именем пользователя, не меняются: ссылка могла быть создана пользователем.
+		script: `UPDATE url SET owner = 'key:' || owner
+		WHERE owner NOT LIKE 'key:%' AND owner IN (SELECT owner FROM api_keys)
+		AND owner NOT IN (SELECT username FROM users)`,
+	},
+	{
+		version: 3,
+		name:    "release_deleted_users_urls",
+		// Ссылки удалённых ранее пользователей остаются без владельца
+		script: `UPDATE url SET owner = ''
+		WHERE owner <> '' AND owner NOT LIKE 'key:%' AND owner NOT IN (SELECT username FROM users)`,
+	},
+}
+
+// migrationLock — ключ рекомендательной блокировки, под которой одновременно
+// запускаемые реплики применяют миграции по очереди.
+const migrationLock = 0x55524c697465
+
+// migrate применяет ещё не применённые миграции в одной транзакции и записывает их версии
+// в таблицу schema_migrations.
+func migrate(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec("SELECT pg_advisory_xact_lock($1)", migrationLock); err != nil {
+		return err
+	}
+
+	applied, err := appliedVersions(tx)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if applied[m.version] {
+			continue
+		}
+
+		if _, err := tx.Exec(m.script); err != nil {
+			return fmt.Errorf("migration %d_%s: %w", m.version, m.name, err)
+		}
+
+		_, err := tx.Exec("INSERT INTO schema_migrations(version, name, applied_at) VALUES($1, $2, $3)",
+			m.version, m.name, time.Now().Unix())
+		if err != nil {
+			return fmt.Errorf("migration %d_%s: %w", m.version, m.name, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func appliedVersions(tx *sql.Tx) (map[int]bool, error) {
+	rows, err := tx.Query("SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	applied := make(map[int]bool)
+
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+
+		applied[version] = true
+	}
+
+	return applied, rows.Err()
+}
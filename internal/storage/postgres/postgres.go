@@ -0,0 +1,581 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq" // init postgres driver
+
+	"URLite/internal/storage"
+)
+
+// uniqueViolation — код ошибки PostgreSQL при нарушении ограничения уникальности.
+const uniqueViolation = "23505"
+
+// hostExpr извлекает имя хоста из столбца url так же, как url.URL.Hostname.
+const hostExpr = `lower(substring(url from '^[^:/?#]+://(?:[^@/?#]*@)?([^:/?#]*)'))`
+
+type Storage struct {
+	db *sql.DB
+}
+
+func New(dsn string) (*Storage, error) {
+	const op = "storage.postgres.New"
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = db.Exec(`
+	CREATE TABLE IF NOT EXISTS url(
+		id BIGSERIAL PRIMARY KEY,
+		alias TEXT NOT NULL UNIQUE,
+		url TEXT NOT NULL,
+		expires_at TIMESTAMPTZ);
+	CREATE INDEX IF NOT EXISTS idx_url_expires_at ON url(expires_at) WHERE expires_at IS NOT NULL;
+	CREATE TABLE IF NOT EXISTS clicks(
+		id BIGSERIAL PRIMARY KEY,
+		alias TEXT NOT NULL,
+		clicked_at TIMESTAMPTZ NOT NULL,
+		referrer TEXT NOT NULL DEFAULT '',
+		user_agent TEXT NOT NULL DEFAULT '',
+		remote_ip TEXT NOT NULL DEFAULT '',
+		request_id TEXT NOT NULL DEFAULT '');
+	CREATE INDEX IF NOT EXISTS idx_clicks_alias_time ON clicks(alias, clicked_at);
+	CREATE TABLE IF NOT EXISTS api_keys(
+		id BIGSERIAL PRIMARY KEY,
+		name TEXT NOT NULL,
+		owner TEXT NOT NULL,
+		prefix TEXT NOT NULL,
+		hash TEXT NOT NULL UNIQUE,
+		created_at TIMESTAMPTZ NOT NULL,
+		revoked_at TIMESTAMPTZ);
+	ALTER TABLE url ADD COLUMN IF NOT EXISTS owner TEXT NOT NULL DEFAULT '';
+	CREATE INDEX IF NOT EXISTS idx_url_owner ON url(owner);
+	CREATE INDEX IF NOT EXISTS idx_url_owner_url ON url(owner, url);
+	CREATE TABLE IF NOT EXISTS users(
+		id BIGSERIAL PRIMARY KEY,
+		username TEXT NOT NULL UNIQUE,
+		password_hash TEXT NOT NULL,
+		role TEXT NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL);
+	ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS role TEXT NOT NULL DEFAULT 'editor';
+	ALTER TABLE url ADD COLUMN IF NOT EXISTS original_url TEXT NOT NULL DEFAULT '';
+	CREATE TABLE IF NOT EXISTS schema_migrations(
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at BIGINT NOT NULL);
+	`)
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := migrate(db); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Storage{db: db}, nil
+}
+
+// Ping проверяет, что база данных доступна.
+func (s *Storage) Ping(ctx context.Context) error {
+	const op = "storage.postgres.Ping"
+
+	if err := s.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Close закрывает соединение с базой данных.
+func (s *Storage) Close() error {
+	const op = "storage.postgres.Close"
+
+	if err := s.db.Close(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// urlColumns — столбцы таблицы url в порядке, ожидаемом scanURL.
+const urlColumns = "id, alias, url, original_url, owner, expires_at"
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanURL(row scanner) (storage.URL, error) {
+	var (
+		u         storage.URL
+		expiresAt sql.NullTime
+	)
+
+	if err := row.Scan(&u.ID, &u.Alias, &u.URL, &u.OriginalURL, &u.Owner, &expiresAt); err != nil {
+		return storage.URL{}, err
+	}
+
+	if expiresAt.Valid {
+		t := expiresAt.Time.UTC()
+		u.ExpiresAt = &t
+	}
+
+	return u, nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
+
+// SaveURL сохраняет новую ссылку. Поле ID у u игнорируется, идентификатор записи возвращается.
+func (s *Storage) SaveURL(u storage.URL) (int64, error) {
+	const op = "storage.postgres.SaveURL"
+
+	var id int64
+
+	err := s.db.QueryRow(
+		"INSERT INTO url(url, original_url, alias, owner, expires_at) VALUES($1, $2, $3, $4, $5) RETURNING id",
+		u.URL, u.OriginalURL, u.Alias, u.Owner, u.ExpiresAt,
+	).Scan(&id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrURLExists)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (s *Storage) GetURL(alias string) (string, error) {
+	const op = "storage.postgres.GetURL"
+
+	var (
+		resURL    string
+		expiresAt sql.NullTime
+	)
+
+	err := s.db.QueryRow("SELECT url, expires_at FROM url WHERE alias = $1", alias).Scan(&resURL, &expiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+		}
+
+		return "", fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	if expiresAt.Valid && !time.Now().Before(expiresAt.Time) {
+		return "", fmt.Errorf("%s: %w", op, storage.ErrURLExpired)
+	}
+
+	return resURL, nil
+}
+
+// GetURLInfo возвращает ссылку с псевдонимом alias со всеми метаданными.
+func (s *Storage) GetURLInfo(alias string) (storage.URL, error) {
+	const op = "storage.postgres.GetURLInfo"
+
+	u, err := scanURL(s.db.QueryRow("SELECT "+urlColumns+" FROM url WHERE alias = $1", alias))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.URL{}, fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+		}
+
+		return storage.URL{}, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	return u, nil
+}
+
+// FindURL возвращает самую раннюю действующую ссылку владельца owner на адрес target.
+func (s *Storage) FindURL(owner, target string) (storage.URL, error) {
+	const op = "storage.postgres.FindURL"
+
+	u, err := scanURL(s.db.QueryRow(
+		"SELECT "+urlColumns+" FROM url"+
+			" WHERE owner = $1 AND url = $2 AND (expires_at IS NULL OR expires_at > now())"+
+			" ORDER BY id LIMIT 1",
+		owner, target,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.URL{}, fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+		}
+
+		return storage.URL{}, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	return u, nil
+}
+
+// ListURLs возвращает страницу ссылок, отфильтрованных и отсортированных согласно params.
+func (s *Storage) ListURLs(params storage.ListParams) (storage.URLPage, error) {
+	const op = "storage.postgres.ListURLs"
+
+	var (
+		conds []string
+		args  []any
+	)
+
+	// arg добавляет аргумент запроса и возвращает его плейсхолдер.
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if params.AliasPrefix != "" {
+		p := arg(params.AliasPrefix)
+		conds = append(conds, "left(alias, length("+p+")) = "+p)
+	}
+
+	if params.Host != "" {
+		conds = append(conds, hostExpr+" = "+arg(strings.ToLower(params.Host)))
+	}
+
+	// Псевдонимы сравниваются побайтно, как в остальных хранилищах.
+	column := "id"
+	if params.SortBy == storage.SortByAlias {
+		column = `alias COLLATE "C"`
+	}
+
+	cmp, order := ">", "ASC"
+	if params.Desc {
+		cmp, order = "<", "DESC"
+	}
+
+	if params.Cursor != "" {
+		cursor, err := storage.DecodeCursor(params.Cursor)
+		if err != nil {
+			return storage.URLPage{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if params.SortBy == storage.SortByAlias {
+			conds = append(conds, column+" "+cmp+" "+arg(cursor.Alias))
+		} else {
+			conds = append(conds, column+" "+cmp+" "+arg(cursor.ID))
+		}
+	}
+
+	query := "SELECT " + urlColumns + " FROM url"
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	// Запрашиваем на одну запись больше, чтобы понять, есть ли следующая страница.
+	query += " ORDER BY " + column + " " + order + " LIMIT " + arg(params.Limit+1)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return storage.URLPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	page := storage.URLPage{URLs: make([]storage.URL, 0, params.Limit)}
+
+	for rows.Next() {
+		u, err := scanURL(rows)
+		if err != nil {
+			return storage.URLPage{}, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+
+		page.URLs = append(page.URLs, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return storage.URLPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(page.URLs) > params.Limit {
+		page.URLs = page.URLs[:params.Limit]
+		page.NextCursor = storage.EncodeCursor(page.URLs[len(page.URLs)-1])
+	}
+
+	return page, nil
+}
+
+// UpdateURL атомарно изменяет поля ссылки с указанным псевдонимом.
+// При смене псевдонима переходы по ссылке переносятся вместе с ней.
+func (s *Storage) UpdateURL(alias string, upd storage.URLUpdate) error {
+	const op = "storage.postgres.UpdateURL"
+
+	var (
+		columns []string
+		args    []any
+	)
+
+	if upd.URL != nil {
+		args = append(args, *upd.URL)
+		columns = append(columns, "url = $"+strconv.Itoa(len(args)))
+	}
+
+	if upd.OriginalURL != nil {
+		args = append(args, *upd.OriginalURL)
+		columns = append(columns, "original_url = $"+strconv.Itoa(len(args)))
+	}
+
+	if upd.ClearExpiresAt {
+		columns = append(columns, "expires_at = NULL")
+	} else if upd.ExpiresAt != nil {
+		args = append(args, *upd.ExpiresAt)
+		columns = append(columns, "expires_at = $"+strconv.Itoa(len(args)))
+	}
+
+	if upd.Alias != nil {
+		args = append(args, *upd.Alias)
+		columns = append(columns, "alias = $"+strconv.Itoa(len(args)))
+	}
+
+	if len(columns) == 0 {
+		return fmt.Errorf("%s: nothing to update", op)
+	}
+
+	args = append(args, alias)
+	query := "UPDATE url SET " + strings.Join(columns, ", ") + " WHERE alias = $" + strconv.Itoa(len(args))
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.Exec(query, args...)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("%s: %w", op, storage.ErrURLExists)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+	}
+
+	if upd.Alias != nil && *upd.Alias != alias {
+		if _, err := tx.Exec("UPDATE clicks SET alias = $1 WHERE alias = $2", *upd.Alias, alias); err != nil {
+			return fmt.Errorf("%s: move clicks: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteURL удаляет ссылку вместе с её переходами, чтобы новая ссылка
+// с тем же псевдонимом не унаследовала чужую статистику.
+func (s *Storage) DeleteURL(alias string) error {
+	const op = "storage.postgres.DeleteURL"
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.Exec("DELETE FROM url WHERE alias = $1", alias)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+	}
+
+	if _, err := tx.Exec("DELETE FROM clicks WHERE alias = $1", alias); err != nil {
+		return fmt.Errorf("%s: delete clicks: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteExpired удаляет ссылки, срок действия которых истёк к моменту now, вместе с их переходами.
+// Возвращает число удалённых ссылок.
+func (s *Storage) DeleteExpired(now time.Time) (int64, error) {
+	const op = "storage.postgres.DeleteExpired"
+
+	var deleted int64
+
+	err := s.db.QueryRow(`
+	WITH expired AS (
+		DELETE FROM url WHERE expires_at IS NOT NULL AND expires_at <= $1 RETURNING alias
+	), clicks_deleted AS (
+		DELETE FROM clicks WHERE alias IN (SELECT alias FROM expired)
+	)
+	SELECT COUNT(*) FROM expired`, now).Scan(&deleted)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deleted, nil
+}
+
+// SaveClicks сохраняет пачку событий переходов в одной транзакции.
+// Переходы по ссылкам, удалённым до сохранения пачки, отбрасываются.
+func (s *Storage) SaveClicks(clicks []storage.Click) error {
+	const op = "storage.postgres.SaveClicks"
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.Prepare(`
+	INSERT INTO clicks(alias, clicked_at, referrer, user_agent, remote_ip, request_id)
+	SELECT $1::text, $2::timestamptz, $3::text, $4::text, $5::text, $6::text
+	WHERE EXISTS(SELECT 1 FROM url WHERE alias = $1)`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = stmt.Close() }()
+
+	for _, c := range clicks {
+		_, err := stmt.Exec(c.Alias, c.Timestamp, c.Referrer, c.UserAgent, c.RemoteIP, c.RequestID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetURLStats возвращает статистику переходов по ссылке за период, заданный в q.
+func (s *Storage) GetURLStats(alias string, q storage.StatsQuery) (storage.URLStats, error) {
+	const op = "storage.postgres.GetURLStats"
+
+	var stats storage.URLStats
+
+	tx, err := s.db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return stats, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var exists bool
+	if err := tx.QueryRow("SELECT EXISTS(SELECT 1 FROM url WHERE alias = $1)", alias).Scan(&exists); err != nil {
+		return stats, fmt.Errorf("%s: %w", op, err)
+	}
+	if !exists {
+		return stats, fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+	}
+
+	err = tx.QueryRow(`
+	SELECT COUNT(*), COUNT(DISTINCT (remote_ip, user_agent))
+	FROM clicks WHERE alias = $1 AND clicked_at >= $2 AND clicked_at < $3`,
+		alias, q.From, q.To,
+	).Scan(&stats.TotalClicks, &stats.UniqueVisitors)
+	if err != nil {
+		return stats, fmt.Errorf("%s: totals: %w", op, err)
+	}
+
+	rows, err := tx.Query(`
+	SELECT (extract(epoch FROM clicked_at)::bigint / $1) * $1 AS bucket, COUNT(*)
+	FROM clicks WHERE alias = $2 AND clicked_at >= $3 AND clicked_at < $4
+	GROUP BY bucket ORDER BY bucket`,
+		storage.IntervalSeconds(q.Interval), alias, q.From, q.To,
+	)
+	if err != nil {
+		return stats, fmt.Errorf("%s: series: %w", op, err)
+	}
+
+	for rows.Next() {
+		var (
+			start int64
+			b     storage.StatsBucket
+		)
+		if err := rows.Scan(&start, &b.Clicks); err != nil {
+			_ = rows.Close()
+			return stats, fmt.Errorf("%s: series: %w", op, err)
+		}
+
+		b.Start = time.Unix(start, 0).UTC()
+		stats.Series = append(stats.Series, b)
+	}
+	if err := closeRows(rows); err != nil {
+		return stats, fmt.Errorf("%s: series: %w", op, err)
+	}
+
+	stats.TopReferrers, err = queryCounts(tx, `
+	SELECT referrer, COUNT(*) AS cnt
+	FROM clicks WHERE alias = $1 AND clicked_at >= $2 AND clicked_at < $3 AND referrer != ''
+	GROUP BY referrer ORDER BY cnt DESC, referrer LIMIT $4`,
+		alias, q.From, q.To, q.Top,
+	)
+	if err != nil {
+		return stats, fmt.Errorf("%s: referrers: %w", op, err)
+	}
+
+	stats.UserAgents, err = queryCounts(tx, `
+	SELECT user_agent, COUNT(*) AS cnt
+	FROM clicks WHERE alias = $1 AND clicked_at >= $2 AND clicked_at < $3
+	GROUP BY user_agent ORDER BY cnt DESC, user_agent`,
+		alias, q.From, q.To,
+	)
+	if err != nil {
+		return stats, fmt.Errorf("%s: user agents: %w", op, err)
+	}
+
+	return stats, nil
+}
+
+func queryCounts(tx *sql.Tx, query string, args ...any) ([]storage.StatsCount, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var counts []storage.StatsCount
+
+	for rows.Next() {
+		var c storage.StatsCount
+		if err := rows.Scan(&c.Value, &c.Count); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+
+		counts = append(counts, c)
+	}
+
+	return counts, closeRows(rows)
+}
+
+func closeRows(rows *sql.Rows) error {
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return err
+	}
+
+	return rows.Close()
+}
@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"URLite/internal/storage"
+)
+
+// userColumns — столбцы таблицы users в порядке, ожидаемом scanUser.
+const userColumns = "id, username, password_hash, role, created_at"
+
+func scanUser(row scanner) (storage.User, error) {
+	var u storage.User
+
+	if err := row.Scan(&u.ID, &u.Username, &u.PasswordHash, &u.Role, &u.CreatedAt); err != nil {
+		return storage.User{}, err
+	}
+
+	u.CreatedAt = u.CreatedAt.UTC()
+
+	return u, nil
+}
+
+// SaveUser сохраняет нового пользователя и возвращает его идентификатор.
+func (s *Storage) SaveUser(u storage.User) (int64, error) {
+	const op = "storage.postgres.SaveUser"
+
+	var id int64
+
+	err := s.db.QueryRow(
+		"INSERT INTO users(username, password_hash, role, created_at) VALUES($1, $2, $3, $4) RETURNING id",
+		u.Username, u.PasswordHash, u.Role, u.CreatedAt,
+	).Scan(&id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrUserExists)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// GetUser возвращает пользователя по имени.
+func (s *Storage) GetUser(username string) (storage.User, error) {
+	const op = "storage.postgres.GetUser"
+
+	u, err := scanUser(s.db.QueryRow("SELECT "+userColumns+" FROM users WHERE username = $1", username))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.User{}, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+		}
+
+		return storage.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return u, nil
+}
+
+// ListUsers возвращает всех пользователей в порядке создания.
+func (s *Storage) ListUsers() ([]storage.User, error) {
+	const op = "storage.postgres.ListUsers"
+
+	rows, err := s.db.Query("SELECT " + userColumns + " FROM users ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	users := []storage.User{}
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		users = append(users, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return users, nil
+}
+
+// DeleteUser удаляет пользователя по имени. Ссылки пользователя не удаляются, а остаются без владельца,
+// чтобы их не унаследовал новый пользователь с тем же именем.
+func (s *Storage) DeleteUser(username string) error {
+	const op = "storage.postgres.DeleteUser"
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.Exec("DELETE FROM users WHERE username = $1", username)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	if _, err := tx.Exec("UPDATE url SET owner = '' WHERE owner = $1", username); err != nil {
+		return fmt.Errorf("%s: release urls: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"URLite/internal/storage"
+)
+
+// apiKeyColumns — столбцы таблицы api_keys в порядке, ожидаемом scanAPIKey.
+const apiKeyColumns = "id, name, owner, role, prefix, hash, created_at, revoked_at"
+
+func scanAPIKey(row scanner) (storage.APIKey, error) {
+	var (
+		k         storage.APIKey
+		createdAt int64
+		revokedAt sql.NullInt64
+	)
+
+	if err := row.Scan(&k.ID, &k.Name, &k.Owner, &k.Role, &k.Prefix, &k.Hash, &createdAt, &revokedAt); err != nil {
+		return storage.APIKey{}, err
+	}
+
+	k.CreatedAt = time.Unix(createdAt, 0).UTC()
+	k.RevokedAt = fromUnix(revokedAt)
+
+	return k, nil
+}
+
+// SaveAPIKey сохраняет новый ключ доступа и возвращает его идентификатор.
+func (s *Storage) SaveAPIKey(k storage.APIKey) (int64, error) {
+	const op = "storage.sqlite.SaveAPIKey"
+
+	res, err := s.db.Exec(
+		"INSERT INTO api_keys(name, owner, role, prefix, hash, created_at) VALUES(?, ?, ?, ?, ?, ?)",
+		k.Name, k.Owner, k.Role, k.Prefix, k.Hash, k.CreatedAt.Unix(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to get last insert id: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// GetAPIKey возвращает ключ доступа по хешу, в том числе отозванный.
+func (s *Storage) GetAPIKey(hash string) (storage.APIKey, error) {
+	const op = "storage.sqlite.GetAPIKey"
+
+	k, err := scanAPIKey(s.db.QueryRow("SELECT "+apiKeyColumns+" FROM api_keys WHERE hash = ?", hash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.APIKey{}, fmt.Errorf("%s: %w", op, storage.ErrAPIKeyNotFound)
+		}
+
+		return storage.APIKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return k, nil
+}
+
+// ListAPIKeys возвращает все ключи доступа в порядке создания.
+func (s *Storage) ListAPIKeys() ([]storage.APIKey, error) {
+	const op = "storage.sqlite.ListAPIKeys"
+
+	rows, err := s.db.Query("SELECT " + apiKeyColumns + " FROM api_keys ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	keys := []storage.APIKey{}
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		keys = append(keys, k)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey отзывает ключ доступа. Повторный отзыв не меняет момент отзыва.
+func (s *Storage) RevokeAPIKey(id int64, at time.Time) error {
+	const op = "storage.sqlite.RevokeAPIKey"
+
+	res, err := s.db.Exec("UPDATE api_keys SET revoked_at = coalesce(revoked_at, ?) WHERE id = ?", at.Unix(), id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrAPIKeyNotFound)
+	}
+
+	return nil
+}
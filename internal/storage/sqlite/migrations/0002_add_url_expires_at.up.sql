@@ -0,0 +1,2 @@
+ALTER TABLE url ADD COLUMN expires_at INTEGER;
+CREATE INDEX idx_url_expires_at ON url(expires_at) WHERE expires_at IS NOT NULL;
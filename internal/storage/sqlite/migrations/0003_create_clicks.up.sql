@@ -0,0 +1,9 @@
+CREATE TABLE clicks(
+	id INTEGER PRIMARY KEY,
+	alias TEXT NOT NULL,
+	clicked_at INTEGER NOT NULL,
+	referrer TEXT NOT NULL DEFAULT '',
+	user_agent TEXT NOT NULL DEFAULT '',
+	remote_ip TEXT NOT NULL DEFAULT '',
+	request_id TEXT NOT NULL DEFAULT '');
+CREATE INDEX idx_clicks_alias_time ON clicks(alias, clicked_at);
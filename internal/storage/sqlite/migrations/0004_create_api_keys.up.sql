@@ -0,0 +1,8 @@
+CREATE TABLE api_keys(
+	id INTEGER PRIMARY KEY,
+	name TEXT NOT NULL,
+	owner TEXT NOT NULL,
+	prefix TEXT NOT NULL,
+	hash TEXT NOT NULL UNIQUE,
+	created_at INTEGER NOT NULL,
+	revoked_at INTEGER);
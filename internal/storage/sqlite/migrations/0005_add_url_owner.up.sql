@@ -0,0 +1,2 @@
+ALTER TABLE url ADD COLUMN owner TEXT NOT NULL DEFAULT '';
+CREATE INDEX idx_url_owner ON url(owner);
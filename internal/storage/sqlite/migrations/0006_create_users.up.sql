@@ -0,0 +1,6 @@
+CREATE TABLE users(
+	id INTEGER PRIMARY KEY,
+	username TEXT NOT NULL UNIQUE,
+	password_hash TEXT NOT NULL,
+	role TEXT NOT NULL,
+	created_at INTEGER NOT NULL);
@@ -0,0 +1 @@
+DROP INDEX idx_url_owner_url;
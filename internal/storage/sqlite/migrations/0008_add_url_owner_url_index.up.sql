@@ -0,0 +1 @@
+CREATE INDEX idx_url_owner_url ON url(owner, url);
@@ -0,0 +1 @@
+ALTER TABLE url ADD COLUMN original_url TEXT NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+-- Удалённые переходы не восстанавливаются
@@ -0,0 +1 @@
+DELETE FROM clicks WHERE alias NOT IN (SELECT alias FROM url);
@@ -0,0 +1 @@
+UPDATE url SET owner = substr(owner, 5) WHERE owner LIKE 'key:%';
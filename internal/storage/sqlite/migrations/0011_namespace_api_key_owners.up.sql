@@ -0,0 +1,3 @@
+UPDATE url SET owner = 'key:' || owner
+WHERE owner NOT LIKE 'key:%' AND owner IN (SELECT owner FROM api_keys)
+	AND owner NOT IN (SELECT username FROM users);
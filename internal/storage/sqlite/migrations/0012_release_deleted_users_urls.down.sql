@@ -0,0 +1 @@
+-- Прежние владельцы ссылок не восстанавливаются
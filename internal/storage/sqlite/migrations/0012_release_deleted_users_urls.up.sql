@@ -0,0 +1,3 @@
+-- Ссылки удалённых ранее пользователей остаются без владельца
+UPDATE url SET owner = ''
+WHERE owner <> '' AND owner NOT LIKE 'key:%' AND owner NOT IN (SELECT username FROM users);
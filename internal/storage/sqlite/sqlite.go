@@ -2,13 +2,47 @@ package sqlite
 
 import (
 	"URLite/internal/storage"
+	"URLite/internal/storage/migrator"
+	"context"
 	"database/sql"
+	"embed"
 	"errors"
 	"fmt"
+	"io/fs"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/mattn/go-sqlite3" // init sqlite3 driver
 )
 
+// driverName — драйвер sqlite3 с зарегистрированными функциями URLite.
+const driverName = "sqlite3_urlite"
+
+func init() {
+	sql.Register(driverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			// Пул открывает несколько соединений к одному файлу: вместо немедленной ошибки
+			// "database is locked" конкурентные записи ждут освобождения блокировки.
+			if _, err := conn.Exec("PRAGMA busy_timeout = 5000", nil); err != nil {
+				return err
+			}
+
+			return conn.RegisterFunc("url_host", urlHost, true)
+		},
+	})
+}
+
+// urlHost возвращает имя хоста из URL в нижнем регистре.
+func urlHost(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+
+	return strings.ToLower(u.Hostname())
+}
+
 type Storage struct {
 	db *sql.DB
 }
@@ -16,39 +50,107 @@ type Storage struct {
 func New(storagePath string) (*Storage, error) {
 	const op = "storage.sqlite.New"
 
-	db, err := sql.Open("sqlite3", storagePath)
+	db, err := sql.Open(driverName, storagePath)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	stmt, err := db.Prepare(`
-	CREATE TABLE IF NOT EXISTS url(
-		id INTEGER PRIMARY KEY,
-		alias TEXT NOT NULL UNIQUE,
-		url TEXT NOT NULL);
-	CREATE INDEX IF NOT EXISTS idx_alias ON url(alias);
-	`)
+	return &Storage{db: db}, nil
+}
+
+// Ping проверяет, что база данных доступна.
+func (s *Storage) Ping(ctx context.Context) error {
+	const op = "storage.sqlite.Ping"
+
+	if err := s.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Close закрывает соединение с базой данных.
+func (s *Storage) Close() error {
+	const op = "storage.sqlite.Close"
+
+	if err := s.db.Close(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Migrator возвращает мигратор схемы базы данных. Схема не создаётся в New:
+// перед использованием хранилища нужно применить миграции.
+func (s *Storage) Migrator() (*migrator.Migrator, error) {
+	const op = "storage.sqlite.Migrator"
+
+	fsys, err := fs.Sub(migrations, "migrations")
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	_, err = stmt.Exec()
+	m, err := migrator.New(s.db, fsys)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return &Storage{db: db}, nil
+	return m, nil
 }
 
-func (s *Storage) SaveURL(urlToSave string, alias string) (int64, error) {
+// urlColumns — столбцы таблицы url в порядке, ожидаемом scanURL.
+const urlColumns = "id, alias, url, original_url, owner, expires_at"
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanURL(row scanner) (storage.URL, error) {
+	var (
+		u         storage.URL
+		expiresAt sql.NullInt64
+	)
+
+	if err := row.Scan(&u.ID, &u.Alias, &u.URL, &u.OriginalURL, &u.Owner, &expiresAt); err != nil {
+		return storage.URL{}, err
+	}
+
+	u.ExpiresAt = fromUnix(expiresAt)
+
+	return u, nil
+}
+
+func toUnix(t *time.Time) sql.NullInt64 {
+	if t == nil {
+		return sql.NullInt64{}
+	}
+
+	return sql.NullInt64{Int64: t.Unix(), Valid: true}
+}
+
+func fromUnix(v sql.NullInt64) *time.Time {
+	if !v.Valid {
+		return nil
+	}
+
+	t := time.Unix(v.Int64, 0).UTC()
+
+	return &t
+}
+
+// SaveURL сохраняет новую ссылку. Поле ID у u игнорируется, идентификатор записи возвращается.
+func (s *Storage) SaveURL(u storage.URL) (int64, error) {
 	const op = "storage.sqlite.SaveURL"
 
-	stmt, err := s.db.Prepare("INSERT INTO url(url, alias) VALUES(?, ?)")
+	stmt, err := s.db.Prepare("INSERT INTO url(url, original_url, alias, owner, expires_at) VALUES(?, ?, ?, ?, ?)")
 	if err != nil {
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
-	res, err := stmt.Exec(urlToSave, alias)
+	res, err := stmt.Exec(u.URL, u.OriginalURL, u.Alias, u.Owner, toUnix(u.ExpiresAt))
 	if err != nil {
 		// TODO: refactor this
 		if sqliteErr, ok := err.(sqlite3.Error); ok && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
@@ -69,33 +171,241 @@ func (s *Storage) SaveURL(urlToSave string, alias string) (int64, error) {
 func (s *Storage) GetURL(alias string) (string, error) {
 	const op = "storage.sqlite.GetURL"
 
-	stmt, err := s.db.Prepare("SELECT url FROM url WHERE alias = ?")
+	stmt, err := s.db.Prepare("SELECT url, expires_at FROM url WHERE alias = ?")
 	if err != nil {
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
 
-	var resURL string
+	var (
+		resURL    string
+		expiresAt sql.NullInt64
+	)
 
-	err = stmt.QueryRow(alias).Scan(&resURL)
+	err = stmt.QueryRow(alias).Scan(&resURL, &expiresAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return "", storage.ErrURLNotFound
+			return "", fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
 		}
 
 		return "", fmt.Errorf("%s: execute statement: %w", op, err)
 	}
+
+	if expiresAt.Valid && expiresAt.Int64 <= time.Now().Unix() {
+		return "", fmt.Errorf("%s: %w", op, storage.ErrURLExpired)
+	}
+
 	return resURL, nil
 }
 
+// GetURLInfo возвращает ссылку с псевдонимом alias со всеми метаданными.
+func (s *Storage) GetURLInfo(alias string) (storage.URL, error) {
+	const op = "storage.sqlite.GetURLInfo"
+
+	stmt, err := s.db.Prepare("SELECT " + urlColumns + " FROM url WHERE alias = ?")
+	if err != nil {
+		return storage.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	u, err := scanURL(stmt.QueryRow(alias))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.URL{}, fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+		}
+
+		return storage.URL{}, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	return u, nil
+}
+
+// FindURL возвращает самую раннюю действующую ссылку владельца owner на адрес target.
+// Поиск использует индекс idx_url_owner_url.
+func (s *Storage) FindURL(owner, target string) (storage.URL, error) {
+	const op = "storage.sqlite.FindURL"
+
+	stmt, err := s.db.Prepare("SELECT " + urlColumns + " FROM url" +
+		" WHERE owner = ? AND url = ? AND (expires_at IS NULL OR expires_at > ?)" +
+		" ORDER BY id LIMIT 1")
+	if err != nil {
+		return storage.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	u, err := scanURL(stmt.QueryRow(owner, target, time.Now().Unix()))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.URL{}, fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+		}
+
+		return storage.URL{}, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	return u, nil
+}
+
+// ListURLs возвращает страницу ссылок, отфильтрованных и отсортированных согласно params.
+func (s *Storage) ListURLs(params storage.ListParams) (storage.URLPage, error) {
+	const op = "storage.sqlite.ListURLs"
+
+	var (
+		conds []string
+		args  []any
+	)
+
+	if params.AliasPrefix != "" {
+		conds = append(conds, "substr(alias, 1, length(?)) = ?")
+		args = append(args, params.AliasPrefix, params.AliasPrefix)
+	}
+
+	if params.Host != "" {
+		conds = append(conds, "url_host(url) = ?")
+		args = append(args, strings.ToLower(params.Host))
+	}
+
+	column := "id"
+	if params.SortBy == storage.SortByAlias {
+		column = "alias"
+	}
+
+	cmp, order := ">", "ASC"
+	if params.Desc {
+		cmp, order = "<", "DESC"
+	}
+
+	if params.Cursor != "" {
+		cursor, err := storage.DecodeCursor(params.Cursor)
+		if err != nil {
+			return storage.URLPage{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		conds = append(conds, column+" "+cmp+" ?")
+		if column == "alias" {
+			args = append(args, cursor.Alias)
+		} else {
+			args = append(args, cursor.ID)
+		}
+	}
+
+	query := "SELECT " + urlColumns + " FROM url"
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	query += " ORDER BY " + column + " " + order + " LIMIT ?"
+
+	// Запрашиваем на одну запись больше, чтобы понять, есть ли следующая страница.
+	rows, err := s.db.Query(query, append(args, params.Limit+1)...)
+	if err != nil {
+		return storage.URLPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	page := storage.URLPage{URLs: make([]storage.URL, 0, params.Limit)}
+
+	for rows.Next() {
+		u, err := scanURL(rows)
+		if err != nil {
+			return storage.URLPage{}, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+
+		page.URLs = append(page.URLs, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return storage.URLPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(page.URLs) > params.Limit {
+		page.URLs = page.URLs[:params.Limit]
+		page.NextCursor = storage.EncodeCursor(page.URLs[len(page.URLs)-1])
+	}
+
+	return page, nil
+}
+
+// UpdateURL атомарно изменяет поля ссылки с указанным псевдонимом.
+// При смене псевдонима переходы по ссылке переносятся вместе с ней.
+func (s *Storage) UpdateURL(alias string, upd storage.URLUpdate) error {
+	const op = "storage.sqlite.UpdateURL"
+
+	var (
+		columns []string
+		args    []any
+	)
+
+	if upd.URL != nil {
+		columns = append(columns, "url = ?")
+		args = append(args, *upd.URL)
+	}
+
+	if upd.OriginalURL != nil {
+		columns = append(columns, "original_url = ?")
+		args = append(args, *upd.OriginalURL)
+	}
+
+	if upd.ClearExpiresAt {
+		columns = append(columns, "expires_at = NULL")
+	} else if upd.ExpiresAt != nil {
+		columns = append(columns, "expires_at = ?")
+		args = append(args, upd.ExpiresAt.Unix())
+	}
+
+	if upd.Alias != nil {
+		columns = append(columns, "alias = ?")
+		args = append(args, *upd.Alias)
+	}
+
+	if len(columns) == 0 {
+		return fmt.Errorf("%s: nothing to update", op)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.Exec("UPDATE url SET "+strings.Join(columns, ", ")+" WHERE alias = ?", append(args, alias)...)
+	if err != nil {
+		if sqliteErr, ok := err.(sqlite3.Error); ok && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return fmt.Errorf("%s: %w", op, storage.ErrURLExists)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+	}
+
+	if upd.Alias != nil && *upd.Alias != alias {
+		if _, err := tx.Exec("UPDATE clicks SET alias = ? WHERE alias = ?", *upd.Alias, alias); err != nil {
+			return fmt.Errorf("%s: move clicks: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteURL удаляет ссылку вместе с её переходами, чтобы новая ссылка
+// с тем же псевдонимом не унаследовала чужую статистику.
 func (s *Storage) DeleteURL(alias string) error {
 	const op = "storage.sqlite.DeleteURL"
 
-	stmt, err := s.db.Prepare("DELETE FROM url WHERE alias = ?")
+	tx, err := s.db.Begin()
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
+	defer func() { _ = tx.Rollback() }()
 
-	res, err := stmt.Exec(alias)
+	res, err := tx.Exec("DELETE FROM url WHERE alias = ?", alias)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -106,8 +416,197 @@ func (s *Storage) DeleteURL(alias string) error {
 	}
 
 	if rowsAffected == 0 {
-		return storage.ErrURLNotFound
+		return fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+	}
+
+	if _, err := tx.Exec("DELETE FROM clicks WHERE alias = ?", alias); err != nil {
+		return fmt.Errorf("%s: delete clicks: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteExpired удаляет ссылки, срок действия которых истёк к моменту now, вместе с их переходами.
+// Возвращает число удалённых ссылок.
+func (s *Storage) DeleteExpired(now time.Time) (int64, error) {
+	const op = "storage.sqlite.DeleteExpired"
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.Exec(`
+	DELETE FROM clicks WHERE alias IN (
+		SELECT alias FROM url WHERE expires_at IS NOT NULL AND expires_at <= ?)`, now.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("%s: delete clicks: %w", op, err)
+	}
+
+	res, err := tx.Exec("DELETE FROM url WHERE expires_at IS NOT NULL AND expires_at <= ?", now.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deleted, nil
+}
+
+// SaveClicks сохраняет пачку событий переходов в одной транзакции.
+// Переходы по ссылкам, удалённым до сохранения пачки, отбрасываются.
+func (s *Storage) SaveClicks(clicks []storage.Click) error {
+	const op = "storage.sqlite.SaveClicks"
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.Prepare(`
+	INSERT INTO clicks(alias, clicked_at, referrer, user_agent, remote_ip, request_id)
+	SELECT ?1, ?2, ?3, ?4, ?5, ?6 WHERE EXISTS(SELECT 1 FROM url WHERE alias = ?1)`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = stmt.Close() }()
+
+	for _, c := range clicks {
+		_, err := stmt.Exec(c.Alias, c.Timestamp.Unix(), c.Referrer, c.UserAgent, c.RemoteIP, c.RequestID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
 	}
 
 	return nil
 }
+
+// GetURLStats возвращает статистику переходов по ссылке за период, заданный в q.
+func (s *Storage) GetURLStats(alias string, q storage.StatsQuery) (storage.URLStats, error) {
+	const op = "storage.sqlite.GetURLStats"
+
+	var stats storage.URLStats
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return stats, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var exists bool
+	if err := tx.QueryRow("SELECT EXISTS(SELECT 1 FROM url WHERE alias = ?)", alias).Scan(&exists); err != nil {
+		return stats, fmt.Errorf("%s: %w", op, err)
+	}
+	if !exists {
+		return stats, fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+	}
+
+	from, to := q.From.Unix(), q.To.Unix()
+
+	err = tx.QueryRow(`
+	SELECT COUNT(*), COUNT(DISTINCT remote_ip || '|' || user_agent)
+	FROM clicks WHERE alias = ? AND clicked_at >= ? AND clicked_at < ?`,
+		alias, from, to,
+	).Scan(&stats.TotalClicks, &stats.UniqueVisitors)
+	if err != nil {
+		return stats, fmt.Errorf("%s: totals: %w", op, err)
+	}
+
+	step := storage.IntervalSeconds(q.Interval)
+
+	rows, err := tx.Query(`
+	SELECT (clicked_at / ?) * ? AS bucket, COUNT(*)
+	FROM clicks WHERE alias = ? AND clicked_at >= ? AND clicked_at < ?
+	GROUP BY bucket ORDER BY bucket`,
+		step, step, alias, from, to,
+	)
+	if err != nil {
+		return stats, fmt.Errorf("%s: series: %w", op, err)
+	}
+
+	for rows.Next() {
+		var (
+			start int64
+			b     storage.StatsBucket
+		)
+		if err := rows.Scan(&start, &b.Clicks); err != nil {
+			_ = rows.Close()
+			return stats, fmt.Errorf("%s: series: %w", op, err)
+		}
+
+		b.Start = time.Unix(start, 0).UTC()
+		stats.Series = append(stats.Series, b)
+	}
+	if err := closeRows(rows); err != nil {
+		return stats, fmt.Errorf("%s: series: %w", op, err)
+	}
+
+	stats.TopReferrers, err = queryCounts(tx, `
+	SELECT referrer, COUNT(*) AS cnt
+	FROM clicks WHERE alias = ? AND clicked_at >= ? AND clicked_at < ? AND referrer != ''
+	GROUP BY referrer ORDER BY cnt DESC, referrer LIMIT ?`,
+		alias, from, to, q.Top,
+	)
+	if err != nil {
+		return stats, fmt.Errorf("%s: referrers: %w", op, err)
+	}
+
+	stats.UserAgents, err = queryCounts(tx, `
+	SELECT user_agent, COUNT(*) AS cnt
+	FROM clicks WHERE alias = ? AND clicked_at >= ? AND clicked_at < ?
+	GROUP BY user_agent ORDER BY cnt DESC, user_agent`,
+		alias, from, to,
+	)
+	if err != nil {
+		return stats, fmt.Errorf("%s: user agents: %w", op, err)
+	}
+
+	return stats, nil
+}
+
+func queryCounts(tx *sql.Tx, query string, args ...any) ([]storage.StatsCount, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var counts []storage.StatsCount
+
+	for rows.Next() {
+		var c storage.StatsCount
+		if err := rows.Scan(&c.Value, &c.Count); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+
+		counts = append(counts, c)
+	}
+
+	return counts, closeRows(rows)
+}
+
+func closeRows(rows *sql.Rows) error {
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return err
+	}
+
+	return rows.Close()
+}
@@ -0,0 +1,175 @@
+package sqlite_test
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"URLite/internal/storage"
+	"URLite/internal/storage/migrator"
+	"URLite/internal/storage/sqlite"
+	"URLite/internal/storage/storagetest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorage(t *testing.T) {
+	storagetest.RunSuite(t, func(t *testing.T) storagetest.Storage {
+		s, err := sqlite.New(filepath.Join(t.TempDir(), "storage.db"))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = s.Close() })
+
+		m, err := s.Migrator()
+		require.NoError(t, err)
+
+		_, err = m.Up()
+		require.NoError(t, err)
+
+		return s
+	})
+}
+
+func TestMigrations(t *testing.T) {
+	s, err := sqlite.New(filepath.Join(t.TempDir(), "storage.db"))
+	require.NoError(t, err)
+
+	m, err := s.Migrator()
+	require.NoError(t, err)
+
+	applied, err := m.Up()
+	require.NoError(t, err)
+	require.NotEmpty(t, applied)
+
+	// Все миграции должны откатываться и применяться повторно
+	for range applied {
+		_, err := m.Down()
+		require.NoError(t, err)
+	}
+
+	pending, err := m.Pending()
+	require.NoError(t, err)
+	require.Equal(t, len(applied), pending)
+
+	_, err = m.Up()
+	require.NoError(t, err)
+
+	pending, err = m.Pending()
+	require.NoError(t, err)
+	require.Zero(t, pending)
+}
+
+// TestMigrations_LegacyDatabase проверяет обновление базы, созданной версией сервиса без миграций.
+func TestMigrations_LegacyDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "storage.db")
+
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+
+	_, err = db.Exec(`
+	CREATE TABLE url(
+		id INTEGER PRIMARY KEY,
+		alias TEXT NOT NULL UNIQUE,
+		url TEXT NOT NULL);
+	INSERT INTO url(alias, url) VALUES('go', 'https://go.dev/');
+	`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	s, err := sqlite.New(path)
+	require.NoError(t, err)
+
+	m, err := s.Migrator()
+	require.NoError(t, err)
+
+	_, err = m.Up()
+	require.NoError(t, err)
+
+	got, err := s.GetURL("go")
+	require.NoError(t, err)
+	require.Equal(t, "https://go.dev/", got)
+}
+
+// TestMigrations_APIKeyOwners проверяет, что ссылки, созданные по ключам доступа до появления
+// префикса "key:", переходят к владельцу ключа, а ссылки пользователей остаются за ними.
+func TestMigrations_APIKeyOwners(t *testing.T) {
+	s, err := sqlite.New(filepath.Join(t.TempDir(), "storage.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+
+	m, err := s.Migrator()
+	require.NoError(t, err)
+
+	_, err = m.Up()
+	require.NoError(t, err)
+
+	// Откатываем миграции до 0011 включительно и заполняем базу данными в прежнем формате
+	downTo(t, m, 11)
+
+	_, err = s.SaveUser(storage.User{Username: "alice", PasswordHash: "hash", Role: storage.RoleEditor})
+	require.NoError(t, err)
+	for i, owner := range []string{"team-a", "alice"} {
+		_, err = s.SaveAPIKey(storage.APIKey{Name: owner, Owner: owner, Hash: fmt.Sprint("hash-", i)})
+		require.NoError(t, err)
+	}
+	for _, owner := range []string{"team-a", "alice"} {
+		_, err = s.SaveURL(storage.URL{Alias: owner, URL: "https://go.dev/", Owner: owner})
+		require.NoError(t, err)
+	}
+
+	_, err = m.Up()
+	require.NoError(t, err)
+
+	u, err := s.GetURLInfo("team-a")
+	require.NoError(t, err)
+	require.Equal(t, "key:team-a", u.Owner)
+
+	// Владелец совпадает и с ключом, и с пользователем: ссылка остаётся за пользователем
+	u, err = s.GetURLInfo("alice")
+	require.NoError(t, err)
+	require.Equal(t, "alice", u.Owner)
+}
+
+func TestMigrations_ReleaseDeletedUsersURLs(t *testing.T) {
+	s, err := sqlite.New(filepath.Join(t.TempDir(), "storage.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+
+	m, err := s.Migrator()
+	require.NoError(t, err)
+
+	_, err = m.Up()
+	require.NoError(t, err)
+
+	downTo(t, m, 12)
+
+	// Ссылки пользователя, удалённого до 0012, остаются под его именем
+	_, err = s.SaveUser(storage.User{Username: "alice", PasswordHash: "hash", Role: storage.RoleEditor})
+	require.NoError(t, err)
+	for _, owner := range []string{"alice", "bob", "key:team-a", ""} {
+		_, err = s.SaveURL(storage.URL{Alias: "link-" + owner, URL: "https://go.dev/", Owner: owner})
+		require.NoError(t, err)
+	}
+
+	_, err = m.Up()
+	require.NoError(t, err)
+
+	for alias, owner := range map[string]string{"link-alice": "alice", "link-bob": "", "link-key:team-a": "key:team-a", "link-": ""} {
+		u, err := s.GetURLInfo(alias)
+		require.NoError(t, err)
+		require.Equal(t, owner, u.Owner, alias)
+	}
+}
+
+// downTo откатывает миграции, пока не будет откачена миграция version.
+func downTo(t *testing.T, m *migrator.Migrator, version int) {
+	t.Helper()
+
+	for {
+		mig, err := m.Down()
+		require.NoError(t, err)
+
+		if mig.Version == version {
+			return
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"URLite/internal/storage"
+	"github.com/mattn/go-sqlite3"
+)
+
+// userColumns — столбцы таблицы users в порядке, ожидаемом scanUser.
+const userColumns = "id, username, password_hash, role, created_at"
+
+func scanUser(row scanner) (storage.User, error) {
+	var (
+		u         storage.User
+		createdAt int64
+	)
+
+	if err := row.Scan(&u.ID, &u.Username, &u.PasswordHash, &u.Role, &createdAt); err != nil {
+		return storage.User{}, err
+	}
+
+	u.CreatedAt = time.Unix(createdAt, 0).UTC()
+
+	return u, nil
+}
+
+// SaveUser сохраняет нового пользователя и возвращает его идентификатор.
+func (s *Storage) SaveUser(u storage.User) (int64, error) {
+	const op = "storage.sqlite.SaveUser"
+
+	res, err := s.db.Exec(
+		"INSERT INTO users(username, password_hash, role, created_at) VALUES(?, ?, ?, ?)",
+		u.Username, u.PasswordHash, u.Role, u.CreatedAt.Unix(),
+	)
+	if err != nil {
+		if sqliteErr, ok := err.(sqlite3.Error); ok && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrUserExists)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to get last insert id: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// GetUser возвращает пользователя по имени.
+func (s *Storage) GetUser(username string) (storage.User, error) {
+	const op = "storage.sqlite.GetUser"
+
+	u, err := scanUser(s.db.QueryRow("SELECT "+userColumns+" FROM users WHERE username = ?", username))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.User{}, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+		}
+
+		return storage.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return u, nil
+}
+
+// ListUsers возвращает всех пользователей в порядке создания.
+func (s *Storage) ListUsers() ([]storage.User, error) {
+	const op = "storage.sqlite.ListUsers"
+
+	rows, err := s.db.Query("SELECT " + userColumns + " FROM users ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	users := []storage.User{}
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		users = append(users, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return users, nil
+}
+
+// DeleteUser удаляет пользователя по имени. Ссылки пользователя не удаляются, а остаются без владельца,
+// чтобы их не унаследовал новый пользователь с тем же именем.
+func (s *Storage) DeleteUser(username string) error {
+	const op = "storage.sqlite.DeleteUser"
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.Exec("DELETE FROM users WHERE username = ?", username)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	if _, err := tx.Exec("UPDATE url SET owner = '' WHERE owner = ?", username); err != nil {
+		return fmt.Errorf("%s: release urls: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
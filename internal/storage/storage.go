@@ -1,8 +1,196 @@
 package storage
 
-import "errors"
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
 
 var (
-	ErrURLNotFound = errors.New("url not found")
-	ErrURLExists   = errors.New("url exists")
+	ErrURLNotFound    = errors.New("url not found")
+	ErrURLExists      = errors.New("url exists")
+	ErrInvalidCursor  = errors.New("invalid cursor")
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	ErrUserNotFound   = errors.New("user not found")
+	ErrUserExists     = errors.New("user exists")
+	// ErrURLExpired возвращается для ссылок с истёкшим сроком действия.
+	// Для errors.Is такая ссылка также считается ErrURLNotFound.
+	ErrURLExpired = fmt.Errorf("%w: expired", ErrURLNotFound)
+)
+
+// Интервалы группировки статистики переходов.
+const (
+	IntervalHour = "hour"
+	IntervalDay  = "day"
+)
+
+// Роли пользователей и ключей доступа, от наименьших прав к наибольшим.
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+// Поля, по которым можно сортировать список ссылок.
+const (
+	SortByID    = "id"
+	SortByAlias = "alias"
 )
+
+// URL — сохранённая короткая ссылка со всеми метаданными.
+type URL struct {
+	ID    int64  `json:"id"`
+	Alias string `json:"alias"`
+	// URL — канонический адрес, на который ведёт ссылка.
+	URL string `json:"url"`
+	// OriginalURL — адрес в том виде, в котором его передал клиент.
+	// Пустая строка у ссылок, созданных до появления нормализации адресов.
+	OriginalURL string `json:"original_url,omitempty"`
+	// Owner — владелец ссылки, создавший её. Пустая строка — ссылка без владельца.
+	Owner string `json:"owner,omitempty"`
+	// ExpiresAt — момент, после которого ссылка перестаёт работать. nil — бессрочная ссылка.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Expired сообщает, истёк ли срок действия ссылки к моменту now.
+func (u URL) Expired(now time.Time) bool {
+	return u.ExpiresAt != nil && !now.Before(*u.ExpiresAt)
+}
+
+// URLUpdate описывает изменения ссылки. Поля со значением nil не изменяются.
+type URLUpdate struct {
+	URL         *string
+	OriginalURL *string
+	ExpiresAt   *time.Time
+	// ClearExpiresAt снимает срок действия ссылки, ExpiresAt при этом не учитывается.
+	ClearExpiresAt bool
+	// Alias — новый псевдоним ссылки. Если он занят, возвращается ErrURLExists.
+	// Переходы, записанные под прежним псевдонимом, переносятся на новый.
+	Alias *string
+}
+
+// APIKey — ключ доступа к API. Сам ключ не хранится, только его хеш.
+type APIKey struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Owner string `json:"owner"`
+	Role  string `json:"role"`
+	// Prefix — начало ключа, по которому его можно узнать в списке.
+	Prefix    string     `json:"prefix"`
+	Hash      string     `json:"-"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Revoked сообщает, отозван ли ключ.
+func (k APIKey) Revoked() bool {
+	return k.RevokedAt != nil
+}
+
+// User — учётная запись пользователя. Пароль хранится только в виде bcrypt-хеша.
+type User struct {
+	ID           int64     `json:"id"`
+	Username     string    `json:"username"`
+	PasswordHash string    `json:"-"`
+	Role         string    `json:"role"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Click — событие перехода по короткой ссылке.
+type Click struct {
+	Alias     string
+	Timestamp time.Time
+	Referrer  string
+	UserAgent string
+	RemoteIP  string
+	RequestID string
+}
+
+// StatsQuery — параметры выборки статистики переходов по ссылке.
+// Учитываются переходы из полуинтервала [From, To).
+type StatsQuery struct {
+	From     time.Time
+	To       time.Time
+	Interval string
+	// Top — сколько самых частых источников переходов и браузеров вернуть.
+	Top int
+}
+
+// URLStats — агрегированная статистика переходов по ссылке.
+type URLStats struct {
+	TotalClicks    int64
+	UniqueVisitors int64
+	// Series содержит только интервалы, в которых были переходы.
+	Series       []StatsBucket
+	TopReferrers []StatsCount
+	// UserAgents содержит число переходов для каждого встреченного User-Agent.
+	UserAgents []StatsCount
+}
+
+// StatsBucket — число переходов за интервал, начинающийся в Start.
+type StatsBucket struct {
+	Start  time.Time `json:"start"`
+	Clicks int64     `json:"clicks"`
+}
+
+// StatsCount — число переходов с определённым значением (источником, браузером).
+type StatsCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// IntervalSeconds возвращает длительность интервала группировки в секундах.
+func IntervalSeconds(interval string) int64 {
+	if interval == IntervalHour {
+		return int64(time.Hour / time.Second)
+	}
+
+	return int64(24 * time.Hour / time.Second)
+}
+
+// ListParams — параметры постраничной выборки ссылок.
+type ListParams struct {
+	Limit       int
+	Cursor      string
+	SortBy      string
+	Desc        bool
+	AliasPrefix string
+	Host        string
+}
+
+// URLPage — страница списка ссылок. NextCursor пуст, если страница последняя.
+type URLPage struct {
+	URLs       []URL  `json:"urls"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Cursor — позиция в списке ссылок, после которой начинается следующая страница.
+type Cursor struct {
+	ID    int64  `json:"id"`
+	Alias string `json:"alias"`
+}
+
+// EncodeCursor возвращает непрозрачный курсор, указывающий на ссылку u.
+func EncodeCursor(u URL) string {
+	data, _ := json.Marshal(Cursor{ID: u.ID, Alias: u.Alias})
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor разбирает курсор, полученный от EncodeCursor.
+func DecodeCursor(cursor string) (Cursor, error) {
+	var c Cursor
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, ErrInvalidCursor
+	}
+
+	return c, nil
+}
@@ -0,0 +1,694 @@
+// Package storagetest содержит общий набор тестов, которому должно соответствовать
+// каждое хранилище ссылок.
+package storagetest
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"URLite/internal/storage"
+)
+
+// Storage — контракт хранилища ссылок, проверяемый RunSuite.
+type Storage interface {
+	SaveURL(u storage.URL) (int64, error)
+	GetURL(alias string) (string, error)
+	GetURLInfo(alias string) (storage.URL, error)
+	FindURL(owner, target string) (storage.URL, error)
+	ListURLs(params storage.ListParams) (storage.URLPage, error)
+	UpdateURL(alias string, upd storage.URLUpdate) error
+	DeleteURL(alias string) error
+	DeleteExpired(now time.Time) (int64, error)
+	SaveClicks(clicks []storage.Click) error
+	GetURLStats(alias string, q storage.StatsQuery) (storage.URLStats, error)
+	SaveAPIKey(k storage.APIKey) (int64, error)
+	GetAPIKey(hash string) (storage.APIKey, error)
+	ListAPIKeys() ([]storage.APIKey, error)
+	RevokeAPIKey(id int64, at time.Time) error
+	SaveUser(u storage.User) (int64, error)
+	GetUser(username string) (storage.User, error)
+	ListUsers() ([]storage.User, error)
+	DeleteUser(username string) error
+}
+
+// Factory возвращает пустое хранилище. Вызывается отдельно для каждого теста набора.
+type Factory func(t *testing.T) Storage
+
+// RunSuite проверяет хранилище, созданное newStorage, на соответствие общему контракту.
+func RunSuite(t *testing.T, newStorage Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s Storage)
+	}{
+		{"SaveAndGet", testSaveAndGet},
+		{"DuplicateAlias", testDuplicateAlias},
+		{"GetMissing", testGetMissing},
+		{"GetURLInfo", testGetURLInfo},
+		{"FindURL", testFindURL},
+		{"Update", testUpdate},
+		{"UpdateMissing", testUpdateMissing},
+		{"ClearExpiration", testClearExpiration},
+		{"Rename", testRename},
+		{"Delete", testDelete},
+		{"DeleteMissing", testDeleteMissing},
+		{"Expiration", testExpiration},
+		{"DeleteExpired", testDeleteExpired},
+		{"ListPagination", testListPagination},
+		{"ListSortByAlias", testListSortByAlias},
+		{"ListFilters", testListFilters},
+		{"ListInvalidCursor", testListInvalidCursor},
+		{"Stats", testStats},
+		{"StatsMissing", testStatsMissing},
+		{"StatsAfterDelete", testStatsAfterDelete},
+		{"StatsAfterRename", testStatsAfterRename},
+		{"APIKeys", testAPIKeys},
+		{"APIKeyMissing", testAPIKeyMissing},
+		{"Users", testUsers},
+		{"UserMissing", testUserMissing},
+		{"DeleteUserReleasesURLs", testDeleteUserReleasesURLs},
+		{"ConcurrentSaveSameAlias", testConcurrentSaveSameAlias},
+		{"ConcurrentSaveAndGet", testConcurrentSaveAndGet},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			tc.fn(t, newStorage(t))
+		})
+	}
+}
+
+func mustSave(t *testing.T, s Storage, alias, rawURL string) int64 {
+	t.Helper()
+
+	id, err := s.SaveURL(storage.URL{Alias: alias, URL: rawURL})
+	require.NoError(t, err)
+
+	return id
+}
+
+func testSaveAndGet(t *testing.T, s Storage) {
+	id1 := mustSave(t, s, "go", "https://go.dev/")
+	id2 := mustSave(t, s, "example", "https://example.com/")
+
+	require.NotZero(t, id1)
+	require.NotEqual(t, id1, id2)
+
+	got, err := s.GetURL("go")
+	require.NoError(t, err)
+	require.Equal(t, "https://go.dev/", got)
+}
+
+func testDuplicateAlias(t *testing.T, s Storage) {
+	mustSave(t, s, "go", "https://go.dev/")
+
+	_, err := s.SaveURL(storage.URL{Alias: "go", URL: "https://example.com/"})
+	require.ErrorIs(t, err, storage.ErrURLExists)
+
+	// Исходная ссылка не должна измениться
+	got, err := s.GetURL("go")
+	require.NoError(t, err)
+	require.Equal(t, "https://go.dev/", got)
+}
+
+func testGetMissing(t *testing.T, s Storage) {
+	_, err := s.GetURL("missing")
+	require.ErrorIs(t, err, storage.ErrURLNotFound)
+	require.NotErrorIs(t, err, storage.ErrURLExpired)
+
+	_, err = s.GetURLInfo("missing")
+	require.ErrorIs(t, err, storage.ErrURLNotFound)
+}
+
+func testGetURLInfo(t *testing.T, s Storage) {
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	id, err := s.SaveURL(storage.URL{
+		Alias:       "go",
+		URL:         "https://go.dev/",
+		OriginalURL: "HTTPS://Go.dev:443/",
+		Owner:       "team-a",
+		ExpiresAt:   &expiresAt,
+	})
+	require.NoError(t, err)
+
+	u, err := s.GetURLInfo("go")
+	require.NoError(t, err)
+	require.Equal(t, id, u.ID)
+	require.Equal(t, "go", u.Alias)
+	require.Equal(t, "https://go.dev/", u.URL)
+	require.Equal(t, "HTTPS://Go.dev:443/", u.OriginalURL)
+	require.Equal(t, "team-a", u.Owner)
+	require.NotNil(t, u.ExpiresAt)
+	require.WithinDuration(t, expiresAt, *u.ExpiresAt, time.Second)
+}
+
+func testFindURL(t *testing.T, s Storage) {
+	past := time.Now().Add(-time.Hour)
+
+	_, err := s.SaveURL(storage.URL{Alias: "expired", URL: "https://go.dev/", Owner: "team-a", ExpiresAt: &past})
+	require.NoError(t, err)
+	first, err := s.SaveURL(storage.URL{Alias: "first", URL: "https://go.dev/", Owner: "team-a"})
+	require.NoError(t, err)
+	_, err = s.SaveURL(storage.URL{Alias: "second", URL: "https://go.dev/", Owner: "team-a"})
+	require.NoError(t, err)
+	_, err = s.SaveURL(storage.URL{Alias: "foreign", URL: "https://example.com/", Owner: "team-b"})
+	require.NoError(t, err)
+
+	// Истёкшие ссылки пропускаются, из действующих выбирается самая ранняя
+	u, err := s.FindURL("team-a", "https://go.dev/")
+	require.NoError(t, err)
+	require.Equal(t, first, u.ID)
+	require.Equal(t, "first", u.Alias)
+	require.Equal(t, "team-a", u.Owner)
+
+	// Ссылки других владельцев не находятся
+	_, err = s.FindURL("team-a", "https://example.com/")
+	require.ErrorIs(t, err, storage.ErrURLNotFound)
+
+	_, err = s.FindURL("team-a", "https://go.dev")
+	require.ErrorIs(t, err, storage.ErrURLNotFound, "target must match exactly")
+}
+
+func testUpdate(t *testing.T, s Storage) {
+	mustSave(t, s, "go", "https://go.dev/")
+
+	newURL, originalURL := "https://go.dev/doc/", "https://Go.dev/doc/"
+	require.NoError(t, s.UpdateURL("go", storage.URLUpdate{URL: &newURL, OriginalURL: &originalURL}))
+
+	got, err := s.GetURL("go")
+	require.NoError(t, err)
+	require.Equal(t, newURL, got)
+
+	expiresAt := time.Now().Add(time.Hour)
+	require.NoError(t, s.UpdateURL("go", storage.URLUpdate{ExpiresAt: &expiresAt}))
+
+	u, err := s.GetURLInfo("go")
+	require.NoError(t, err)
+	require.Equal(t, newURL, u.URL, "fields missing from the update must be left unchanged")
+	require.Equal(t, originalURL, u.OriginalURL)
+	require.NotNil(t, u.ExpiresAt)
+	require.WithinDuration(t, expiresAt, *u.ExpiresAt, time.Second)
+}
+
+func testClearExpiration(t *testing.T, s Storage) {
+	past := time.Now().Add(-time.Minute)
+
+	_, err := s.SaveURL(storage.URL{Alias: "go", URL: "https://go.dev/", ExpiresAt: &past})
+	require.NoError(t, err)
+
+	require.NoError(t, s.UpdateURL("go", storage.URLUpdate{ClearExpiresAt: true}))
+
+	u, err := s.GetURLInfo("go")
+	require.NoError(t, err)
+	require.Nil(t, u.ExpiresAt)
+
+	got, err := s.GetURL("go")
+	require.NoError(t, err)
+	require.Equal(t, "https://go.dev/", got)
+}
+
+func testUpdateMissing(t *testing.T, s Storage) {
+	newURL := "https://go.dev/"
+
+	err := s.UpdateURL("missing", storage.URLUpdate{URL: &newURL})
+	require.ErrorIs(t, err, storage.ErrURLNotFound)
+}
+
+func testRename(t *testing.T, s Storage) {
+	id := mustSave(t, s, "tmp", "https://go.dev/")
+	mustSave(t, s, "taken", "https://example.com/")
+
+	newAlias := "go"
+	require.NoError(t, s.UpdateURL("tmp", storage.URLUpdate{Alias: &newAlias}))
+
+	u, err := s.GetURLInfo("go")
+	require.NoError(t, err)
+	require.Equal(t, id, u.ID)
+	require.Equal(t, "go", u.Alias)
+	require.Equal(t, "https://go.dev/", u.URL)
+
+	_, err = s.GetURL("tmp")
+	require.ErrorIs(t, err, storage.ErrURLNotFound)
+
+	// Занятый псевдоним не перезаписывается
+	taken := "taken"
+	err = s.UpdateURL("go", storage.URLUpdate{Alias: &taken})
+	require.ErrorIs(t, err, storage.ErrURLExists)
+
+	got, err := s.GetURL("taken")
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/", got)
+}
+
+func testDelete(t *testing.T, s Storage) {
+	mustSave(t, s, "go", "https://go.dev/")
+
+	require.NoError(t, s.DeleteURL("go"))
+
+	_, err := s.GetURL("go")
+	require.ErrorIs(t, err, storage.ErrURLNotFound)
+
+	// Псевдоним удалённой ссылки можно использовать повторно
+	mustSave(t, s, "go", "https://example.com/")
+}
+
+func testDeleteMissing(t *testing.T, s Storage) {
+	require.ErrorIs(t, s.DeleteURL("missing"), storage.ErrURLNotFound)
+}
+
+func testExpiration(t *testing.T, s Storage) {
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+
+	_, err := s.SaveURL(storage.URL{Alias: "old", URL: "https://go.dev/", ExpiresAt: &past})
+	require.NoError(t, err)
+	_, err = s.SaveURL(storage.URL{Alias: "new", URL: "https://go.dev/", ExpiresAt: &future})
+	require.NoError(t, err)
+
+	_, err = s.GetURL("old")
+	require.ErrorIs(t, err, storage.ErrURLExpired)
+	require.ErrorIs(t, err, storage.ErrURLNotFound)
+
+	got, err := s.GetURL("new")
+	require.NoError(t, err)
+	require.Equal(t, "https://go.dev/", got)
+}
+
+func testDeleteExpired(t *testing.T, s Storage) {
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+
+	for i := 0; i < 3; i++ {
+		_, err := s.SaveURL(storage.URL{Alias: fmt.Sprintf("old%d", i), URL: "https://go.dev/", ExpiresAt: &past})
+		require.NoError(t, err)
+	}
+	_, err := s.SaveURL(storage.URL{Alias: "new", URL: "https://go.dev/", ExpiresAt: &future})
+	require.NoError(t, err)
+	mustSave(t, s, "forever", "https://go.dev/")
+
+	deleted, err := s.DeleteExpired(time.Now())
+	require.NoError(t, err)
+	require.EqualValues(t, 3, deleted)
+
+	_, err = s.GetURLInfo("old0")
+	require.ErrorIs(t, err, storage.ErrURLNotFound)
+
+	for _, alias := range []string{"new", "forever"} {
+		_, err := s.GetURL(alias)
+		require.NoError(t, err)
+	}
+}
+
+// listAll обходит все страницы списка и возвращает псевдонимы в порядке выдачи.
+func listAll(t *testing.T, s Storage, params storage.ListParams) []string {
+	t.Helper()
+
+	var aliases []string
+
+	for i := 0; ; i++ {
+		require.Less(t, i, 100, "too many pages")
+
+		page, err := s.ListURLs(params)
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(page.URLs), params.Limit)
+
+		for _, u := range page.URLs {
+			aliases = append(aliases, u.Alias)
+		}
+
+		if page.NextCursor == "" {
+			return aliases
+		}
+
+		params.Cursor = page.NextCursor
+	}
+}
+
+func testListPagination(t *testing.T, s Storage) {
+	var want []string
+	for i := 0; i < 7; i++ {
+		alias := fmt.Sprintf("alias%d", i)
+		mustSave(t, s, alias, "https://go.dev/")
+		want = append(want, alias)
+	}
+
+	got := listAll(t, s, storage.ListParams{Limit: 3, SortBy: storage.SortByID})
+	require.Equal(t, want, got)
+
+	got = listAll(t, s, storage.ListParams{Limit: 3, SortBy: storage.SortByID, Desc: true})
+	for i, j := 0, len(want)-1; i < j; i, j = i+1, j-1 {
+		want[i], want[j] = want[j], want[i]
+	}
+	require.Equal(t, want, got)
+
+	page, err := s.ListURLs(storage.ListParams{Limit: 7, SortBy: storage.SortByID})
+	require.NoError(t, err)
+	require.Len(t, page.URLs, 7)
+	require.Empty(t, page.NextCursor, "an exactly full last page must not have a next cursor")
+}
+
+func testListSortByAlias(t *testing.T, s Storage) {
+	for _, alias := range []string{"b", "a", "C", "c", "B"} {
+		mustSave(t, s, alias, "https://go.dev/")
+	}
+
+	// Псевдонимы сравниваются побайтно: заглавные буквы идут раньше строчных
+	require.Equal(t, []string{"B", "C", "a", "b", "c"},
+		listAll(t, s, storage.ListParams{Limit: 2, SortBy: storage.SortByAlias}))
+	require.Equal(t, []string{"c", "b", "a", "C", "B"},
+		listAll(t, s, storage.ListParams{Limit: 2, SortBy: storage.SortByAlias, Desc: true}))
+}
+
+func testListFilters(t *testing.T, s Storage) {
+	mustSave(t, s, "go-home", "https://Go.dev/")
+	mustSave(t, s, "go-doc", "http://go.dev:8080/doc/")
+	mustSave(t, s, "Go-upper", "https://go.dev/")
+	mustSave(t, s, "go-blog", "https://example.com/go.dev")
+	mustSave(t, s, "example", "https://user@go.dev/path")
+
+	params := storage.ListParams{Limit: 10, SortBy: storage.SortByAlias}
+
+	params.AliasPrefix = "go-"
+	require.Equal(t, []string{"go-blog", "go-doc", "go-home"}, listAll(t, s, params))
+
+	params.AliasPrefix = ""
+	params.Host = "GO.DEV"
+	require.Equal(t, []string{"Go-upper", "example", "go-doc", "go-home"}, listAll(t, s, params))
+
+	params.AliasPrefix = "go"
+	require.Equal(t, []string{"go-doc", "go-home"}, listAll(t, s, params))
+}
+
+func testListInvalidCursor(t *testing.T, s Storage) {
+	_, err := s.ListURLs(storage.ListParams{Limit: 10, SortBy: storage.SortByID, Cursor: "not a cursor"})
+	require.ErrorIs(t, err, storage.ErrInvalidCursor)
+}
+
+func testStats(t *testing.T, s Storage) {
+	mustSave(t, s, "go", "https://go.dev/")
+	mustSave(t, s, "other", "https://example.com/")
+
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, s.SaveClicks([]storage.Click{
+		{Alias: "go", Timestamp: day.Add(1 * time.Hour), Referrer: "https://a.example/", UserAgent: "ua1", RemoteIP: "10.0.0.1"},
+		{Alias: "go", Timestamp: day.Add(2 * time.Hour), Referrer: "https://a.example/", UserAgent: "ua1", RemoteIP: "10.0.0.1"},
+		{Alias: "go", Timestamp: day.Add(2 * time.Hour), Referrer: "https://b.example/", UserAgent: "ua2", RemoteIP: "10.0.0.1"},
+		{Alias: "go", Timestamp: day.Add(26 * time.Hour), UserAgent: "ua1", RemoteIP: "10.0.0.2"},
+		// Вне запрошенного периода
+		{Alias: "go", Timestamp: day.Add(-time.Hour), UserAgent: "ua1", RemoteIP: "10.0.0.3"},
+		{Alias: "go", Timestamp: day.Add(72 * time.Hour), UserAgent: "ua1", RemoteIP: "10.0.0.3"},
+		// Другая ссылка
+		{Alias: "other", Timestamp: day.Add(time.Hour), UserAgent: "ua1", RemoteIP: "10.0.0.1"},
+	}))
+
+	stats, err := s.GetURLStats("go", storage.StatsQuery{
+		From:     day,
+		To:       day.Add(48 * time.Hour),
+		Interval: storage.IntervalDay,
+		Top:      1,
+	})
+	require.NoError(t, err)
+
+	require.EqualValues(t, 4, stats.TotalClicks)
+	require.EqualValues(t, 3, stats.UniqueVisitors)
+	require.Equal(t, []storage.StatsBucket{
+		{Start: day, Clicks: 3},
+		{Start: day.Add(24 * time.Hour), Clicks: 1},
+	}, stats.Series)
+	require.Equal(t, []storage.StatsCount{{Value: "https://a.example/", Count: 2}}, stats.TopReferrers)
+	require.Equal(t, []storage.StatsCount{{Value: "ua1", Count: 3}, {Value: "ua2", Count: 1}}, stats.UserAgents)
+
+	stats, err = s.GetURLStats("go", storage.StatsQuery{
+		From:     day,
+		To:       day.Add(24 * time.Hour),
+		Interval: storage.IntervalHour,
+		Top:      10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []storage.StatsBucket{
+		{Start: day.Add(time.Hour), Clicks: 1},
+		{Start: day.Add(2 * time.Hour), Clicks: 2},
+	}, stats.Series)
+}
+
+func testStatsMissing(t *testing.T, s Storage) {
+	_, err := s.GetURLStats("missing", storage.StatsQuery{
+		From:     time.Now().Add(-time.Hour),
+		To:       time.Now(),
+		Interval: storage.IntervalHour,
+		Top:      10,
+	})
+	require.ErrorIs(t, err, storage.ErrURLNotFound)
+}
+
+// totalClicks возвращает число переходов по ссылке за сутки, начинающиеся в day.
+func totalClicks(t *testing.T, s Storage, alias string, day time.Time) int64 {
+	t.Helper()
+
+	stats, err := s.GetURLStats(alias, storage.StatsQuery{
+		From:     day,
+		To:       day.Add(24 * time.Hour),
+		Interval: storage.IntervalHour,
+		Top:      10,
+	})
+	require.NoError(t, err)
+
+	return stats.TotalClicks
+}
+
+func testStatsAfterDelete(t *testing.T, s Storage) {
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	past := time.Now().Add(-time.Minute)
+
+	mustSave(t, s, "go", "https://go.dev/")
+	_, err := s.SaveURL(storage.URL{Alias: "old", URL: "https://go.dev/", ExpiresAt: &past})
+	require.NoError(t, err)
+
+	require.NoError(t, s.SaveClicks([]storage.Click{
+		{Alias: "go", Timestamp: day.Add(time.Hour), UserAgent: "ua1", RemoteIP: "10.0.0.1"},
+		{Alias: "go", Timestamp: day.Add(2 * time.Hour), UserAgent: "ua1", RemoteIP: "10.0.0.1"},
+		{Alias: "old", Timestamp: day.Add(time.Hour), UserAgent: "ua1", RemoteIP: "10.0.0.1"},
+	}))
+
+	require.NoError(t, s.DeleteURL("go"))
+	_, err = s.DeleteExpired(time.Now())
+	require.NoError(t, err)
+
+	// Переход, записанный уже после удаления ссылки, не сохраняется
+	require.NoError(t, s.SaveClicks([]storage.Click{
+		{Alias: "go", Timestamp: day.Add(3 * time.Hour), UserAgent: "ua1", RemoteIP: "10.0.0.1"},
+	}))
+
+	// Новая ссылка с освободившимся псевдонимом не наследует чужие переходы
+	mustSave(t, s, "go", "https://example.com/")
+	mustSave(t, s, "old", "https://example.com/")
+
+	require.Zero(t, totalClicks(t, s, "go", day))
+	require.Zero(t, totalClicks(t, s, "old", day))
+}
+
+func testStatsAfterRename(t *testing.T, s Storage) {
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mustSave(t, s, "tmp", "https://go.dev/")
+
+	require.NoError(t, s.SaveClicks([]storage.Click{
+		{Alias: "tmp", Timestamp: day.Add(time.Hour), UserAgent: "ua1", RemoteIP: "10.0.0.1"},
+		{Alias: "tmp", Timestamp: day.Add(2 * time.Hour), UserAgent: "ua2", RemoteIP: "10.0.0.2"},
+	}))
+
+	newAlias := "go"
+	require.NoError(t, s.UpdateURL("tmp", storage.URLUpdate{Alias: &newAlias}))
+
+	// Переходы переезжают вместе со ссылкой
+	require.EqualValues(t, 2, totalClicks(t, s, "go", day))
+
+	mustSave(t, s, "tmp", "https://example.com/")
+	require.Zero(t, totalClicks(t, s, "tmp", day))
+}
+
+func testAPIKeys(t *testing.T, s Storage) {
+	createdAt := time.Now().Truncate(time.Second).UTC()
+
+	firstID, err := s.SaveAPIKey(storage.APIKey{Name: "ci", Owner: "team-a", Role: storage.RoleEditor, Prefix: "ulk_1", Hash: "hash-1", CreatedAt: createdAt})
+	require.NoError(t, err)
+
+	secondID, err := s.SaveAPIKey(storage.APIKey{Name: "bot", Owner: "team-b", Prefix: "ulk_2", Hash: "hash-2", CreatedAt: createdAt})
+	require.NoError(t, err)
+	require.NotEqual(t, firstID, secondID)
+
+	k, err := s.GetAPIKey("hash-1")
+	require.NoError(t, err)
+	require.Equal(t, storage.APIKey{
+		ID:        firstID,
+		Name:      "ci",
+		Owner:     "team-a",
+		Role:      storage.RoleEditor,
+		Prefix:    "ulk_1",
+		Hash:      "hash-1",
+		CreatedAt: createdAt,
+	}, k)
+
+	revokedAt := createdAt.Add(time.Hour)
+	require.NoError(t, s.RevokeAPIKey(firstID, revokedAt))
+	// Повторный отзыв не меняет момент отзыва
+	require.NoError(t, s.RevokeAPIKey(firstID, revokedAt.Add(time.Hour)))
+
+	k, err = s.GetAPIKey("hash-1")
+	require.NoError(t, err)
+	require.True(t, k.Revoked())
+	require.True(t, revokedAt.Equal(*k.RevokedAt))
+
+	keys, err := s.ListAPIKeys()
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	require.Equal(t, []int64{firstID, secondID}, []int64{keys[0].ID, keys[1].ID})
+	require.True(t, keys[0].Revoked())
+	require.False(t, keys[1].Revoked())
+}
+
+func testAPIKeyMissing(t *testing.T, s Storage) {
+	_, err := s.GetAPIKey("missing")
+	require.ErrorIs(t, err, storage.ErrAPIKeyNotFound)
+
+	require.ErrorIs(t, s.RevokeAPIKey(42, time.Now()), storage.ErrAPIKeyNotFound)
+
+	keys, err := s.ListAPIKeys()
+	require.NoError(t, err)
+	require.Empty(t, keys)
+}
+
+func testUsers(t *testing.T, s Storage) {
+	createdAt := time.Now().Truncate(time.Second).UTC()
+
+	aliceID, err := s.SaveUser(storage.User{Username: "alice", PasswordHash: "hash-a", Role: storage.RoleAdmin, CreatedAt: createdAt})
+	require.NoError(t, err)
+
+	_, err = s.SaveUser(storage.User{Username: "alice", PasswordHash: "hash-b", Role: storage.RoleViewer, CreatedAt: createdAt})
+	require.ErrorIs(t, err, storage.ErrUserExists)
+
+	bobID, err := s.SaveUser(storage.User{Username: "bob", PasswordHash: "hash-b", Role: storage.RoleViewer, CreatedAt: createdAt})
+	require.NoError(t, err)
+
+	u, err := s.GetUser("alice")
+	require.NoError(t, err)
+	require.Equal(t, storage.User{
+		ID:           aliceID,
+		Username:     "alice",
+		PasswordHash: "hash-a",
+		Role:         storage.RoleAdmin,
+		CreatedAt:    createdAt,
+	}, u)
+
+	users, err := s.ListUsers()
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	require.Equal(t, []int64{aliceID, bobID}, []int64{users[0].ID, users[1].ID})
+
+	require.NoError(t, s.DeleteUser("alice"))
+
+	_, err = s.GetUser("alice")
+	require.ErrorIs(t, err, storage.ErrUserNotFound)
+}
+
+func testUserMissing(t *testing.T, s Storage) {
+	_, err := s.GetUser("missing")
+	require.ErrorIs(t, err, storage.ErrUserNotFound)
+
+	require.ErrorIs(t, s.DeleteUser("missing"), storage.ErrUserNotFound)
+
+	users, err := s.ListUsers()
+	require.NoError(t, err)
+	require.Empty(t, users)
+}
+
+func testDeleteUserReleasesURLs(t *testing.T, s Storage) {
+	createdAt := time.Now().Truncate(time.Second).UTC()
+
+	_, err := s.SaveUser(storage.User{Username: "bob", PasswordHash: "hash-b", Role: storage.RoleEditor, CreatedAt: createdAt})
+	require.NoError(t, err)
+
+	_, err = s.SaveURL(storage.URL{Alias: "bob-link", URL: "https://go.dev/", Owner: "bob"})
+	require.NoError(t, err)
+	_, err = s.SaveURL(storage.URL{Alias: "key-link", URL: "https://go.dev/", Owner: "key:bob"})
+	require.NoError(t, err)
+
+	require.NoError(t, s.DeleteUser("bob"))
+
+	// Ссылка продолжает работать, но больше никому не принадлежит
+	u, err := s.GetURLInfo("bob-link")
+	require.NoError(t, err)
+	require.Empty(t, u.Owner)
+
+	// Ссылки ключа с тем же именем владельца не затрагиваются
+	u, err = s.GetURLInfo("key-link")
+	require.NoError(t, err)
+	require.Equal(t, "key:bob", u.Owner)
+}
+
+func testConcurrentSaveSameAlias(t *testing.T, s Storage) {
+	const workers = 20
+
+	var (
+		wg      sync.WaitGroup
+		saved   atomic.Int64
+		existed atomic.Int64
+	)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := s.SaveURL(storage.URL{Alias: "race", URL: "https://go.dev/"})
+			switch {
+			case err == nil:
+				saved.Add(1)
+			case assert.ErrorIs(t, err, storage.ErrURLExists):
+				existed.Add(1)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	require.EqualValues(t, 1, saved.Load(), "exactly one save must win")
+	require.EqualValues(t, workers-1, existed.Load())
+}
+
+func testConcurrentSaveAndGet(t *testing.T, s Storage) {
+	const workers = 20
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			alias := fmt.Sprintf("alias%d", i)
+			rawURL := fmt.Sprintf("https://go.dev/%d", i)
+
+			if _, err := s.SaveURL(storage.URL{Alias: alias, URL: rawURL}); !assert.NoError(t, err) {
+				return
+			}
+
+			got, err := s.GetURL(alias)
+			assert.NoError(t, err)
+			assert.Equal(t, rawURL, got)
+		}(i)
+	}
+
+	wg.Wait()
+
+	require.Len(t, listAll(t, s, storage.ListParams{Limit: 100, SortBy: storage.SortByID}), workers)
+}
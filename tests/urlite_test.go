@@ -26,14 +26,14 @@ func TestURLShortener_HappyPath(t *testing.T) {
 	}
 	e := httpexpect.Default(t, u.String())
 
-	e.POST("/url").
+	e.POST("/api/v1/url").
 		WithJSON(save.Request{
 			URL:   gofakeit.URL(),
 			Alias: random.NewRandomString(10),
 		}).
 		WithBasicAuth("user1", "pass1").
 		Expect().
-		Status(200).
+		Status(http.StatusCreated).
 		JSON().Object().
 		ContainsKey("alias")
 }
@@ -76,13 +76,18 @@ func TestURLShortener_SaveRedirect(t *testing.T) {
 
 			// Save
 
-			resp := e.POST("/url").
+			status := http.StatusCreated
+			if tc.error != "" {
+				status = http.StatusBadRequest
+			}
+
+			resp := e.POST("/api/v1/url").
 				WithJSON(save.Request{
 					URL:   tc.url,
 					Alias: tc.alias,
 				}).
-				WithBasicAuth("myuser", "mypass").
-				Expect().Status(http.StatusOK).
+				WithBasicAuth("user1", "pass1").
+				Expect().Status(status).
 				JSON().Object()
 
 			if tc.error != "" {
@@ -109,7 +114,7 @@ func TestURLShortener_SaveRedirect(t *testing.T) {
 
 			// Remove
 
-			reqDel := e.DELETE("/"+path.Join("url", alias)).WithBasicAuth("user1", "pass1").Expect().Status(http.StatusOK).JSON().Object()
+			reqDel := e.DELETE("/"+path.Join("api/v1/url", alias)).WithBasicAuth("user1", "pass1").Expect().Status(http.StatusOK).JSON().Object()
 
 			reqDel.Value("status").String().IsEqual("OK")
 